@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"go-env-cli/internal/app/models"
+
+	"github.com/spf13/cobra"
+)
+
+// backupCmd represents the backup command
+var backupCmd = &cobra.Command{
+	Use:   "backup [file]",
+	Short: "Back up every project, environment and variable to a JSON file",
+	Long: `Back up every project, environment and variable, including soft-deleted ones,
+to a portable JSON file that can be restored into any database with the restore command.
+
+Examples:
+  go-env-cli backup backup.json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		filePath := args[0]
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Write backup
+		progress := newProgress("Backing up")
+		backup, err := handler.BackupToFile(filePath, progress.Update)
+		progress.Finish()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating backup: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		printSuccess("Successfully backed up %s to %s\n", backupSummary(backup), filePath)
+	},
+}
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore [file]",
+	Short: "Restore projects, environments and variables from a JSON backup",
+	Long: `Restore projects, environments and variables from a file written by the backup
+command. Existing records are matched by name and key and updated, so restoring the
+same backup more than once is safe.
+
+Examples:
+  go-env-cli restore backup.json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		filePath := args[0]
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Restore backup
+		progress := newProgress("Restoring")
+		backup, err := handler.RestoreFromFile(filePath, progress.Update)
+		progress.Finish()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error restoring backup: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		printSuccess("Successfully restored %s from %s\n", backupSummary(backup), filePath)
+	},
+}
+
+// backupSummary describes the number of records in a backup
+func backupSummary(backup *models.Backup) string {
+	variables := 0
+	for _, p := range backup.Projects {
+		variables += len(p.Variables)
+	}
+
+	return fmt.Sprintf("%d project(s), %d environment(s) and %d variable(s)",
+		len(backup.Projects), len(backup.Environments), variables)
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
+}
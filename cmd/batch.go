@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go-env-cli/internal/app/handlers"
+	"go-env-cli/internal/pkg/utils"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// batchCmd represents the batch command
+var batchCmd = &cobra.Command{
+	Use:   "batch FILE",
+	Short: "Apply a file of set, delete and rename-var commands in one transaction",
+	Long: `Apply a file of set, delete and rename-var commands, one per line, in a single database
+transaction. If any command fails, nothing is changed and the failing line is reported.
+Blank lines and lines starting with # are ignored. Values are quoted as in a shell.
+
+Each line takes the flags of the command it names: --project, --env (default
+development), --key, and --value for set or --new-key for rename-var.
+
+Example file:
+  # Rotate the database credentials
+  set --project api --env prod --key DB_USER --value app_v2
+  set --project api --env prod --key DB_PASSWORD --value 's3cr3t "quoted"'
+  rename-var --project api --env prod --key DB_HOST --new-key DATABASE_HOST
+  delete --project api --env prod --key LEGACY_DSN
+
+Examples:
+  go-env-cli batch changes.txt`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file, err := os.Open(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening batch file: %v\n", err)
+			os.Exit(1)
+		}
+		operations, err := parseBatch(file)
+		file.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading batch file: %v\n", err)
+			os.Exit(ExitValidation)
+		}
+		if len(operations) == 0 {
+			fmt.Printf("No commands found in %s\n", args[0])
+			return
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Apply every command in one transaction
+		if err := handler.ApplyBatch(operations); err != nil {
+			fmt.Fprintf(os.Stderr, "Error applying batch, nothing was changed: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		printSuccess("Successfully applied %d command(s) from %s\n", len(operations), args[0])
+	},
+}
+
+// parseBatch reads the commands of a batch file, checking that each has the flags it needs
+func parseBatch(r io.Reader) ([]handlers.BatchOperation, error) {
+	var operations []handlers.BatchOperation
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		words, err := utils.SplitCommandLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		op := handlers.BatchOperation{Line: lineNumber, Command: words[0]}
+		flags := pflag.NewFlagSet(op.Command, pflag.ContinueOnError)
+		flags.SetOutput(io.Discard)
+		flags.StringVar(&op.Project, "project", "", "")
+		flags.StringVar(&op.Environment, "env", "development", "")
+		flags.StringVar(&op.Key, "key", "", "")
+
+		var required []string
+		switch op.Command {
+		case "set":
+			flags.StringVar(&op.Value, "value", "", "")
+			required = []string{"project", "key", "value"}
+		case "delete":
+			required = []string{"project", "key"}
+		case "rename-var":
+			flags.StringVar(&op.NewKey, "new-key", "", "")
+			required = []string{"project", "key", "new-key"}
+		default:
+			return nil, fmt.Errorf("line %d: unknown command '%s', expected set, delete or rename-var", lineNumber, op.Command)
+		}
+
+		if err := flags.Parse(words[1:]); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		if flags.NArg() > 0 {
+			return nil, fmt.Errorf("line %d: unexpected argument '%s'", lineNumber, flags.Arg(0))
+		}
+		for _, name := range required {
+			if !flags.Changed(name) {
+				return nil, fmt.Errorf("line %d: %s needs --%s", lineNumber, op.Command, name)
+			}
+		}
+
+		operations = append(operations, op)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return operations, nil
+}
+
+func init() {
+	rootCmd.AddCommand(batchCmd)
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"go-env-cli/internal/pkg/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkFile string
+	keysOnly  bool
+)
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Compare a .env file with the stored variables",
+	Long: `Compare a local .env file with the variables stored for a project environment and
+report keys whose values differ, keys missing from the database and keys missing from
+the file. Exits with status 1 when they diverge, so it can be used in CI.
+Use --keys-only to compare only the set of keys, e.g. for a .env.example template.
+
+Examples:
+  go-env-cli check --project test --env local --file .env
+  go-env-cli check --project test --env local --file .env.example --keys-only`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
+		}
+		if environmentName == "" {
+			environmentName = "development" // Default to development
+		}
+		if checkFile == "" {
+			fmt.Fprintln(os.Stderr, "Error: --file flag is required")
+			os.Exit(ExitValidation)
+		}
+
+		// Parse the file with the import parser
+		content, err := os.ReadFile(checkFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", checkFile, err)
+			os.Exit(exitCode(err))
+		}
+
+		fileValues, err := parseEnvMap(string(content))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing %s: %v\n", checkFile, err)
+			os.Exit(exitCode(err))
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Get stored variables
+		variables, err := handler.ListEnvVariables(projectName, environmentName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing environment variables: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		storedValues := make(map[string]string, len(variables))
+		for _, v := range variables {
+			storedValues[v.Key] = v.Value
+		}
+
+		diff := utils.DiffEnv(storedValues, fileValues)
+		if keysOnly {
+			diff.Changed = nil
+		}
+
+		if diff.Empty() {
+			fmt.Printf("%s is in sync with project '%s' (%s environment)\n", checkFile, projectName, environmentName)
+			return
+		}
+
+		fmt.Printf("%s differs from project '%s' (%s environment):\n", checkFile, projectName, environmentName)
+		for _, c := range diff.Changed {
+			fmt.Println(colors.Changed(fmt.Sprintf("~ %s: value differs", c.Key)))
+		}
+		for _, c := range diff.Added {
+			fmt.Println(colors.Added(fmt.Sprintf("+ %s: missing from the database", c.Key)))
+		}
+		for _, c := range diff.Removed {
+			fmt.Println(colors.Removed(fmt.Sprintf("- %s: missing from the file", c.Key)))
+		}
+		os.Exit(1)
+	},
+}
+
+func init() {
+	checkCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	checkCmd.Flags().StringVar(&environmentName, "env", "development", "Environment name (default: development)")
+	checkCmd.Flags().StringVar(&checkFile, "file", "", "Path of the .env file to compare (required)")
+	checkCmd.Flags().BoolVar(&keysOnly, "keys-only", false, "Only compare keys, ignoring values")
+	checkCmd.MarkFlagRequired("project")
+	checkCmd.MarkFlagRequired("file")
+	rootCmd.AddCommand(checkCmd)
+}
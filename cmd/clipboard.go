@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// errNoClipboard is returned by copyToClipboard when no clipboard tool is installed
+var errNoClipboard = errors.New("no clipboard tool found")
+
+// clipboardCommands returns the commands that can copy standard input to the
+// clipboard on this system, in order of preference
+func clipboardCommands() [][]string {
+	switch {
+	case runtime.GOOS == "darwin":
+		return [][]string{{"pbcopy"}}
+	case isWindows():
+		return [][]string{{"clip"}}
+	}
+
+	var commands [][]string
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		commands = append(commands, []string{"wl-copy"})
+	}
+	return append(commands,
+		[]string{"xclip", "-selection", "clipboard"},
+		[]string{"xsel", "--clipboard", "--input"},
+	)
+}
+
+// copyToClipboard copies value to the system clipboard with the first clipboard tool
+// found, returning errNoClipboard when there is none
+func copyToClipboard(value string) error {
+	for _, command := range clipboardCommands() {
+		path, err := exec.LookPath(command[0])
+		if err != nil {
+			continue
+		}
+
+		clipCmd := exec.Command(path, command[1:]...)
+		clipCmd.Stdin = strings.NewReader(value)
+		clipCmd.Stderr = os.Stderr
+		return clipCmd.Run()
+	}
+
+	return errNoClipboard
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// cloneEnvironmentsCmd represents the env clone command
+var cloneEnvironmentsCmd = &cobra.Command{
+	Use:   "clone",
+	Short: "Give a project the same environments as another project",
+	Long: `Give a project the same environments as another project, so a new project starts with
+the same environment set.
+
+The target project gets a copy of every environment of the source project, with its
+description and parent, and every key the source project has in each environment is
+added with an empty value, as a placeholder to fill in. Environments and keys the
+target already has are left alone, and the target project is created if it doesn't
+exist. Use clone-project to copy the values too.
+
+Example:
+  go-env-cli env clone --from-project billing-service --to-project invoicing-service`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if fromProjectName == "" || toProjectName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --from-project and --to-project flags are required")
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Clone environments
+		counts, err := handler.CloneEnvironments(fromProjectName, toProjectName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error cloning environments: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		if len(counts) == 0 {
+			fmt.Printf("Project '%s' has no environments to clone\n", fromProjectName)
+			return
+		}
+		for _, c := range counts {
+			printSuccess("- %s: %d placeholder(s) added\n", c.Name, c.Count)
+		}
+		printSuccess("Successfully cloned %d environment(s) from '%s' to '%s'\n", len(counts), fromProjectName, toProjectName)
+	},
+}
+
+func init() {
+	cloneEnvironmentsCmd.Flags().StringVar(&fromProjectName, "from-project", "", "Project whose environments are cloned (required)")
+	cloneEnvironmentsCmd.Flags().StringVar(&toProjectName, "to-project", "", "Project that gets the environments (required)")
+	cloneEnvironmentsCmd.MarkFlagRequired("from-project")
+	cloneEnvironmentsCmd.MarkFlagRequired("to-project")
+	cloneEnvironmentsCmd.RegisterFlagCompletionFunc("from-project", completeProjectNames)
+	cloneEnvironmentsCmd.RegisterFlagCompletionFunc("to-project", completeProjectNames)
+	environmentCmd.AddCommand(cloneEnvironmentsCmd)
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	fromProjectName string
+	toProjectName   string
+)
+
+// cloneProjectCmd represents the clone-project command
+var cloneProjectCmd = &cobra.Command{
+	Use:   "clone-project",
+	Short: "Copy a project and all its variables to a new project",
+	Long: `Create a new project with a copy of every variable of an existing project, in all
+environments. Fails if the destination project already exists.
+
+Example:
+  go-env-cli clone-project --from billing-service --to invoicing-service`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if fromProjectName == "" || toProjectName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --from and --to flags are required")
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Clone project
+		err = handler.CloneProject(fromProjectName, toProjectName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error cloning project: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		printSuccess("Successfully cloned project '%s' to '%s'\n", fromProjectName, toProjectName)
+	},
+}
+
+func init() {
+	cloneProjectCmd.Flags().StringVar(&fromProjectName, "from", "", "Source project name (required)")
+	cloneProjectCmd.Flags().StringVar(&toProjectName, "to", "", "New project name (required)")
+	cloneProjectCmd.MarkFlagRequired("from")
+	cloneProjectCmd.MarkFlagRequired("to")
+	cloneProjectCmd.RegisterFlagCompletionFunc("from", completeProjectNames)
+	cloneProjectCmd.RegisterFlagCompletionFunc("to", cobra.NoFileCompletions)
+	rootCmd.AddCommand(cloneProjectCmd)
+}
@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // completionCmd represents the completion command
@@ -69,3 +71,127 @@ PowerShell:
 func init() {
 	rootCmd.AddCommand(completionCmd)
 }
+
+// flagCompletions maps flag names to the function completing their values
+var flagCompletions = map[string]func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective){
+	"project": completeProjectNames,
+	"env":     completeEnvironmentNames,
+	"from":    completeEnvironmentNames,
+	"to":      completeEnvironmentNames,
+	"key":     completeVariableKeys,
+	"filter":  completeVariableKeys,
+}
+
+// registerFlagCompletions attaches dynamic completion to every command that
+// defines one of the flags in flagCompletions. Flags whose completion a command
+// registered itself keep it, since registering a flag twice fails.
+func registerFlagCompletions(cmd *cobra.Command) {
+	cmd.LocalNonPersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		if complete, ok := flagCompletions[flag.Name]; ok {
+			cmd.RegisterFlagCompletionFunc(flag.Name, complete)
+		}
+	})
+
+	for _, child := range cmd.Commands() {
+		registerFlagCompletions(child)
+	}
+}
+
+// completeProjectNames suggests the names of existing projects
+func completeProjectNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	handler, err := initHandler()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	projects, err := handler.ListProjects()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	for _, p := range projects {
+		names = append(names, p.Name)
+	}
+
+	return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeEnvironmentNames suggests the environments of the selected project, or
+// the shared environments when no project has been given yet
+func completeEnvironmentNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	handler, err := initHandler()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	project, _ := cmd.Flags().GetString("project")
+
+	var names []string
+	if project != "" {
+		environments, err := handler.GetEnvironmentsForProject(project)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		for _, e := range environments {
+			names = append(names, e.Name)
+		}
+	} else {
+		environments, err := handler.ListEnvironments("")
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		for _, e := range environments {
+			names = append(names, e.Name)
+		}
+	}
+
+	return filterCompletions(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeVariableKeys suggests the variable keys of the selected project and environment
+func completeVariableKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	project, _ := cmd.Flags().GetString("project")
+	if project == "" {
+		project, _ = cmd.Flags().GetString("from-project")
+	}
+	if project == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	environment, _ := cmd.Flags().GetString("env")
+	if environment == "" {
+		environment, _ = cmd.Flags().GetString("from")
+	}
+	if environment == "" {
+		environment = "development" // Default to development
+	}
+
+	handler, err := initHandler()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	variables, err := handler.ListEnvVariables(project, environment)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var keys []string
+	for _, v := range variables {
+		keys = append(keys, v.Key)
+	}
+
+	return filterCompletions(keys, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// filterCompletions keeps the candidates starting with the text typed so far
+func filterCompletions(candidates []string, toComplete string) []string {
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, toComplete) {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"go-env-cli/config"
+	"go-env-cli/internal/pkg/db"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	nonInteractive bool
+	dbSettings     config.DatabaseConfig
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the go-env-cli configuration",
+}
+
+// configInitCmd represents the config init command
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a configuration file with the database connection settings",
+	Long: `Create a ` + config.FileName + ` file in the current directory, or the file given with
+--config, with the database connection settings. You are prompted for each setting,
+with the flag values as defaults. The connection is tested before the file is written.
+
+Examples:
+  go-env-cli config init
+  go-env-cli --config ~/.go-env-cli.prod.yaml config init
+  go-env-cli config init --non-interactive --host db.internal --user app --password secret --dbname go-env-cli`,
+	Run: func(cmd *cobra.Command, args []string) {
+		settings := dbSettings
+
+		// Write to the --config file when one is given
+		path := config.ExplicitPath(cfgFile)
+		if path == "" {
+			path = config.FileName
+		}
+
+		// Check for an existing file
+		if _, err := os.Stat(path); err == nil && !force {
+			if nonInteractive {
+				fmt.Fprintf(os.Stderr, "Error: %s already exists, use --force to overwrite it\n", path)
+				os.Exit(ExitConflict)
+			}
+
+			fmt.Printf("%s already exists. Overwrite it? (y/n): ", path)
+			var response string
+			fmt.Scanln(&response)
+			if response != "y" && response != "Y" {
+				fmt.Println("Operation cancelled")
+				return
+			}
+		}
+
+		// Prompt for each setting
+		if !nonInteractive {
+			reader := bufio.NewReader(os.Stdin)
+			settings.Host = prompt(reader, "Host", settings.Host)
+			port := prompt(reader, "Port", strconv.Itoa(settings.Port))
+			settings.User = prompt(reader, "User", settings.User)
+			settings.Password = prompt(reader, "Password", settings.Password)
+			settings.DBName = prompt(reader, "Database name", settings.DBName)
+			settings.SSLMode = prompt(reader, "SSL mode", settings.SSLMode)
+
+			p, err := strconv.Atoi(port)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid port '%s'\n", port)
+				os.Exit(ExitValidation)
+			}
+			settings.Port = p
+		}
+
+		// Test the connection before saving
+		fmt.Println("Testing connection...")
+		cfg := config.Config{GO_CLI_DB: settings.DSN(), Database: settings}
+		conn, err := db.NewDB(cfg.DB())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+		conn.Close()
+
+		// Write the file, readable only by the owner since it holds the password
+		content, err := yaml.Marshal(map[string]config.DatabaseConfig{"database": settings})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding config: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+		if err := os.WriteFile(path, content, 0600); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing config: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		printSuccess("Configuration written to %s\n", path)
+	},
+}
+
+// configUseProfileCmd represents the config use-profile command
+var configUseProfileCmd = &cobra.Command{
+	Use:   "use-profile NAME",
+	Short: "Set the database profile used by default",
+	Long: `Set the profile used when neither --profile nor ` + config.ProfileEnvVar + ` is given, by
+writing it to the configuration file. Profiles are database targets listed under
+profiles in the file:
+
+  profile: local
+  profiles:
+    local:
+      host: localhost
+      port: 5432
+      user: postgres
+      password: postgres
+      dbname: go-env-cli
+      sslmode: disable
+    prod:
+      host: db.internal
+      port: 5432
+      user: admin
+      dbname: go-env-cli
+      sslmode: verify-full
+
+Examples:
+  go-env-cli config use-profile prod
+  go-env-cli --profile local list --project test`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Loading the profile checks that it exists
+		cfg, err := config.LoadConfig(cfgFile, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+		if cfg.File == "" {
+			fmt.Fprintf(os.Stderr, "Error: no %s found, run 'go-env-cli config init' first\n", config.FileName)
+			os.Exit(ExitValidation)
+		}
+
+		if err := config.SetDefaultProfile(cfg.File, args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing config: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		printSuccess("Now using profile '%s' from %s\n", args[0], cfg.File)
+	},
+}
+
+// prompt asks for a value on the terminal, returning def when the answer is empty
+func prompt(reader *bufio.Reader, label, def string) string {
+	if def != "" {
+		fmt.Printf("%s [%s]: ", label, def)
+	} else {
+		fmt.Printf("%s: ", label)
+	}
+
+	answer, _ := reader.ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return def
+	}
+	return answer
+}
+
+func init() {
+	configInitCmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "Use the flag values without prompting")
+	configInitCmd.Flags().BoolVarP(&force, "force", "f", false, "Overwrite an existing configuration file")
+	configInitCmd.Flags().StringVar(&dbSettings.Host, "host", "localhost", "Database host")
+	configInitCmd.Flags().IntVar(&dbSettings.Port, "port", 5432, "Database port")
+	configInitCmd.Flags().StringVar(&dbSettings.User, "user", "postgres", "Database user")
+	configInitCmd.Flags().StringVar(&dbSettings.Password, "password", "", "Database password")
+	configInitCmd.Flags().StringVar(&dbSettings.DBName, "dbname", "go-env-cli", "Database name")
+	configInitCmd.Flags().StringVar(&dbSettings.SSLMode, "sslmode", "disable", "SSL mode (disable, require, verify-ca, verify-full)")
+	configInitCmd.Flags().StringVar(&dbSettings.SSLRootCert, "sslrootcert", "", "Path to the CA certificate used to verify the server")
+	configInitCmd.Flags().StringVar(&dbSettings.SSLCert, "sslcert", "", "Path to the client certificate")
+	configInitCmd.Flags().StringVar(&dbSettings.SSLKey, "sslkey", "", "Path to the client private key")
+
+	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(configUseProfileCmd)
+	rootCmd.AddCommand(configCmd)
+}
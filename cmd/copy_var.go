@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var toEnvironmentOverride string
+
+// copyVarCmd represents the copy-var command
+var copyVarCmd = &cobra.Command{
+	Use:   "copy-var",
+	Short: "Copy an environment variable to another project",
+	Long: `Copy an environment variable from one project to another, for example to share a
+generated secret between services. The variable is written to the same environment
+unless --to-env is given; the destination environment is created if needed.
+
+Examples:
+  go-env-cli copy-var --from-project billing --to-project invoicing --env production --key JWT_SECRET
+  go-env-cli copy-var --from-project billing --to-project invoicing --env production --key JWT_SECRET --overwrite`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if fromProjectName == "" || toProjectName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --from-project and --to-project flags are required")
+			os.Exit(ExitValidation)
+		}
+		if keyName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --key flag is required")
+			os.Exit(ExitValidation)
+		}
+		if environmentName == "" {
+			environmentName = "development" // Default to development
+		}
+		targetEnvironment := toEnvironmentOverride
+		if targetEnvironment == "" {
+			targetEnvironment = environmentName
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Copy variable
+		err = handler.CopyEnvVariable(fromProjectName, toProjectName, environmentName, targetEnvironment, keyName, overwrite)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error copying environment variable: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		printSuccess("Successfully copied environment variable '%s' from project '%s' (%s environment) to '%s' (%s environment)\n",
+			keyName, fromProjectName, environmentName, toProjectName, targetEnvironment)
+	},
+}
+
+func init() {
+	copyVarCmd.Flags().StringVar(&fromProjectName, "from-project", "", "Source project name (required)")
+	copyVarCmd.Flags().StringVar(&toProjectName, "to-project", "", "Destination project name (required)")
+	copyVarCmd.Flags().StringVar(&environmentName, "env", "development", "Environment name (default: development)")
+	copyVarCmd.Flags().StringVar(&toEnvironmentOverride, "to-env", "", "Destination environment name (default: same as --env)")
+	copyVarCmd.Flags().StringVar(&keyName, "key", "", "Environment variable key (required)")
+	copyVarCmd.Flags().BoolVar(&overwrite, "overwrite", false, "Replace the variable if it already exists in the destination")
+	copyVarCmd.MarkFlagRequired("from-project")
+	copyVarCmd.MarkFlagRequired("to-project")
+	copyVarCmd.MarkFlagRequired("key")
+	copyVarCmd.RegisterFlagCompletionFunc("from-project", completeProjectNames)
+	copyVarCmd.RegisterFlagCompletionFunc("to-project", completeProjectNames)
+	copyVarCmd.RegisterFlagCompletionFunc("to-env", completeEnvironmentNames)
+	rootCmd.AddCommand(copyVarCmd)
+}
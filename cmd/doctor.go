@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"go-env-cli/config"
+	"go-env-cli/internal/pkg/db"
+
+	"github.com/spf13/cobra"
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:     "doctor",
+	Aliases: []string{"ping"},
+	Short:   "Check the configuration and database connection",
+	Long: `Check that the configuration loads, the database is reachable, the expected
+tables and indexes exist and all migrations are applied. Each check prints PASS or FAIL, and
+failures include a suggested fix. Exits with status 1 when any check fails.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !runDoctor() {
+			os.Exit(1)
+		}
+	},
+}
+
+// runDoctor runs the checks in order, stopping at the first one later checks depend
+// on, and reports whether they all passed
+func runDoctor() bool {
+	// Configuration
+	cfg, err := config.LoadConfig(cfgFile, profileName)
+	if err != nil {
+		path := config.ExplicitPath(cfgFile)
+		if path == "" {
+			path = config.FileName
+		}
+		return checkFailed("Configuration", err.Error(), "check that "+path+" exists and fix its syntax")
+	}
+	if cfg.GO_CLI_DB == "" {
+		return checkFailed("Configuration", "no database configured", "set GO_CLI_DB or run 'go-env-cli config init'")
+	}
+	if err := cfg.Validate(); err != nil {
+		return checkFailed("Configuration", err.Error(), "correct these settings in the configuration file or GO_CLI_DB")
+	}
+	checkPassed("Configuration")
+	if debugSQL {
+		cfg.Database.DebugSQL = true
+	}
+
+	// Connection
+	conn, err := db.NewDB(cfg.DB())
+	if err != nil {
+		return checkFailed("Connection", err.Error(), "make sure PostgreSQL is running (docker compose up -d) and the connection settings are correct")
+	}
+	defer conn.Close()
+	checkPassed("Connection")
+
+	// Query
+	var one int
+	if err := conn.Get(&one, "SELECT 1"); err != nil {
+		return checkFailed("Query", err.Error(), "check that the database user can run queries")
+	}
+	checkPassed("Query")
+
+	ok := true
+
+	// Tables
+	missing, err := db.MissingTables(conn, "projects", "environments", "env_variables")
+	switch {
+	case err != nil:
+		ok = checkFailed("Tables", err.Error(), "check that the database user can read the catalog")
+	case len(missing) > 0:
+		ok = checkFailed("Tables", "missing "+strings.Join(missing, ", "), "run 'make init-db' to apply the migrations")
+	default:
+		checkPassed("Tables")
+	}
+
+	// Indexes
+	missing, err = db.MissingIndexes(conn, db.ExpectedIndexes...)
+	switch {
+	case err != nil:
+		ok = checkFailed("Indexes", err.Error(), "check that the database user can read the catalog")
+	case len(missing) > 0:
+		ok = checkFailed("Indexes", "missing "+strings.Join(missing, ", "), "run 'make init-db' to apply the migrations")
+	default:
+		checkPassed("Indexes")
+	}
+
+	// Migrations
+	migrationsDir := db.FindMigrationsDir()
+	if migrationsDir == "" {
+		fmt.Println("[SKIP] Migrations: migrations directory not found")
+		return ok
+	}
+
+	manager, err := db.NewMigrationManager(conn, migrationsDir)
+	if err != nil {
+		return checkFailed("Migrations", err.Error(), "check the permissions of "+migrationsDir)
+	}
+	pending, err := manager.PendingMigrations()
+	switch {
+	case err != nil:
+		ok = checkFailed("Migrations", err.Error(), "run 'make init-db' to apply the migrations")
+	case len(pending) > 0:
+		ok = checkFailed("Migrations", "pending "+strings.Join(pending, ", "), "run 'make init-db' to apply the migrations")
+	default:
+		checkPassed("Migrations")
+	}
+
+	return ok
+}
+
+// checkPassed prints a passing check
+func checkPassed(name string) {
+	fmt.Printf("[PASS] %s\n", name)
+}
+
+// checkFailed prints a failing check with a suggested fix and returns false
+func checkFailed(name, problem, fix string) bool {
+	fmt.Printf("[FAIL] %s: %s\n", name, problem)
+	fmt.Printf("       Fix: %s\n", fix)
+	return false
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"go-env-cli/internal/app/handlers"
+	"go-env-cli/internal/app/models"
+	"go-env-cli/internal/pkg/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// Edit env variable command
+var editVarCmd = &cobra.Command{
+	Use:   "edit-var",
+	Short: "Edit an environment variable in your editor",
+	Long: `Open the value of an environment variable in $EDITOR and store the result on save.
+This is the easiest way to edit multiline values such as certificates or JSON blobs.
+A variable that doesn't exist yet starts out empty and is created on save.
+
+Examples:
+  go-env-cli edit-var --project test --env local --key GOOGLE_CREDENTIALS`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
+		}
+		if environmentName == "" {
+			environmentName = "development" // Default to development
+		}
+		if keyName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --key flag is required")
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Get the current value, a missing variable starts out empty
+		value, err := handler.GetEnvVariable(projectName, environmentName, keyName)
+		if err != nil && !errors.Is(err, models.ErrVariableNotFound) {
+			fmt.Fprintf(os.Stderr, "Error getting environment variable: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Edit the value
+		edited, err := editInEditor(value, keyName+"-*.txt")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error editing environment variable: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Editors usually append a newline on save, drop it unless the value had one
+		if !strings.HasSuffix(value, "\n") {
+			edited = strings.TrimSuffix(strings.TrimSuffix(edited, "\n"), "\r")
+		}
+
+		if edited == value {
+			fmt.Println("No changes made")
+			return
+		}
+
+		// Store the edited value
+		err = handler.SetEnvVariable(projectName, environmentName, keyName, edited)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error setting environment variable: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		printSuccess("Successfully updated %s for project '%s' (%s environment)\n",
+			keyName, projectName, environmentName)
+	},
+}
+
+// Edit environment command
+var editEnvCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit all variables of an environment in your editor",
+	Long: `Open all variables of a project environment as a .env file in $EDITOR. Edit, add or
+remove lines, save and close the editor, then review the resulting changes. Confirmed
+changes are applied in a single transaction.
+
+Examples:
+  go-env-cli edit --project test --env local`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
+		}
+		if environmentName == "" {
+			environmentName = "development" // Default to development
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Render the current variables as a .env buffer
+		var buf bytes.Buffer
+		if err := handler.ExportEnv(&buf, projectName, environmentName, handlers.ExportOptions{}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering environment variables: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Edit the buffer
+		edited, err := editInEditor(buf.String(), projectName+"-"+environmentName+"-*.env")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error editing environment variables: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Parse both versions with the import parser
+		current, err := parseEnvMap(buf.String())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing environment variables: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		desired, err := parseEnvMap(edited)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing edited file: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		diff := utils.DiffEnv(current, desired)
+		if diff.Empty() {
+			fmt.Println("No changes made")
+			return
+		}
+
+		// Show the changes and confirm
+		printEnvDiff(diff)
+		fmt.Print("Apply these changes? [y/N]: ")
+		var response string
+		fmt.Scanln(&response)
+		if response != "y" && response != "Y" {
+			fmt.Println("Edit cancelled")
+			return
+		}
+
+		// Apply the changes
+		if err := handler.ApplyEnvDiff(projectName, environmentName, diff); err != nil {
+			fmt.Fprintf(os.Stderr, "Error applying changes: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		printSuccess("Successfully applied %d change(s) to project '%s' (%s environment)\n",
+			len(diff.Added)+len(diff.Changed)+len(diff.Removed), projectName, environmentName)
+	},
+}
+
+// parseEnvMap parses .env content into a map of keys to values, later duplicates winning
+func parseEnvMap(content string) (map[string]string, error) {
+	entries, err := utils.ParseEnv(bytes.NewReader(utils.NormalizeNewlines([]byte(content))))
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(entries))
+	for _, e := range entries {
+		values[e.Key] = e.Value
+	}
+
+	return values, nil
+}
+
+// printEnvDiff prints the changes of a diff, one key per line
+func printEnvDiff(diff utils.EnvDiff) {
+	for _, c := range diff.Added {
+		fmt.Println(colors.Added(fmt.Sprintf("+ %s=%s", c.Key, c.NewValue)))
+	}
+	for _, c := range diff.Changed {
+		fmt.Println(colors.Changed(fmt.Sprintf("~ %s: %s -> %s", c.Key, c.OldValue, c.NewValue)))
+	}
+	for _, c := range diff.Removed {
+		fmt.Println(colors.Removed(fmt.Sprintf("- %s", c.Key)))
+	}
+}
+
+// editInEditor writes content to a temporary file, opens it in the user's editor and
+// returns the saved content. The temporary file is only readable by the current user
+// and is removed afterwards.
+func editInEditor(content, pattern string) (string, error) {
+	tmpFile, err := os.CreateTemp("", "go-env-cli-"+pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath)
+
+	if err := tmpFile.Chmod(0600); err != nil {
+		tmpFile.Close()
+		return "", fmt.Errorf("failed to set file permissions: %w", err)
+	}
+
+	if _, err := tmpFile.WriteString(content); err != nil {
+		tmpFile.Close()
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return "", fmt.Errorf("failed to close temporary file: %w", err)
+	}
+
+	// The editor command may include arguments, e.g. "code --wait"
+	editor := strings.Fields(editorCommand())
+	editorCmd := exec.Command(editor[0], append(editor[1:], tmpPath)...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+
+	if err := editorCmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %s failed: %w", editor[0], err)
+	}
+
+	edited, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read edited file: %w", err)
+	}
+
+	return string(edited), nil
+}
+
+// editorCommand returns the editor to use, from $VISUAL or $EDITOR with an OS specific fallback
+func editorCommand() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(name)); editor != "" {
+			return editor
+		}
+	}
+
+	if isWindows() {
+		return "notepad"
+	}
+	return "vi"
+}
+
+func init() {
+	editVarCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	editVarCmd.Flags().StringVar(&environmentName, "env", "development", "Environment name (default: development)")
+	editVarCmd.Flags().StringVar(&keyName, "key", "", "Environment variable key (required)")
+	editVarCmd.MarkFlagRequired("project")
+	editVarCmd.MarkFlagRequired("key")
+	rootCmd.AddCommand(editVarCmd)
+
+	editEnvCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	editEnvCmd.Flags().StringVar(&environmentName, "env", "development", "Environment name (default: development)")
+	editEnvCmd.MarkFlagRequired("project")
+	rootCmd.AddCommand(editEnvCmd)
+}
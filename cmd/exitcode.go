@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"database/sql/driver"
+	"errors"
+	"net"
+
+	"go-env-cli/config"
+	"go-env-cli/internal/app/models"
+	"go-env-cli/internal/pkg/db"
+	"go-env-cli/internal/pkg/utils"
+
+	"github.com/lib/pq"
+)
+
+// Exit codes, so scripts can tell failure categories apart. Usage errors, such as a
+// missing or invalid flag, exit with ExitValidation; anything not listed here exits
+// with ExitError.
+const (
+	ExitError      = 1
+	ExitNotFound   = 2
+	ExitValidation = 3
+	ExitDatabase   = 4
+	ExitConflict   = 5
+)
+
+// exitCode returns the exit code for the category of err
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, models.ErrProjectNotFound),
+		errors.Is(err, models.ErrEnvironmentNotFound),
+		errors.Is(err, models.ErrVariableNotFound),
+		errors.Is(err, models.ErrSnapshotNotFound),
+		errors.Is(err, models.ErrNothingToUndo):
+		return ExitNotFound
+	case errors.Is(err, utils.ErrInvalidValue), errors.Is(err, config.ErrInvalidConfig),
+		errors.Is(err, utils.ErrChecksumMismatch), errors.Is(err, utils.ErrDuplicateKeys),
+		errors.Is(err, utils.ErrResponseTooLarge):
+		return ExitValidation
+	case errors.Is(err, models.ErrAlreadyExists):
+		return ExitConflict
+	case isDatabaseError(err):
+		return ExitDatabase
+	}
+	return ExitError
+}
+
+// isDatabaseError reports whether err comes from connecting to or querying the database
+func isDatabaseError(err error) bool {
+	var pqErr *pq.Error
+	var netErr *net.OpError
+	return errors.Is(err, db.ErrConnection) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.As(err, &pqErr) ||
+		errors.As(err, &netErr)
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"go-env-cli/internal/app/handlers"
+
+	"github.com/spf13/cobra"
+)
+
+var exportDir string
+
+// exportAllCmd represents the export-all command
+var exportAllCmd = &cobra.Command{
+	Use:   "export-all",
+	Short: "Export every environment of a project into a directory",
+	Long: `Export every environment of a project into a directory, writing one file per
+environment named after it (e.g. uat.env, sit.env).
+
+The --key, --only, --exclude and --order flags select and order the variables of
+every file like they do for export.
+
+Examples:
+  go-env-cli export-all --project my-project --dir ./out
+  go-env-cli export-all --project my-project --dir ./out --format json
+  go-env-cli export-all --project my-project --dir ./out --exclude "INTERNAL_*"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
+		}
+		if exportDir == "" {
+			fmt.Fprintln(os.Stderr, "Error: --dir flag is required")
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Export all environments
+		progress := newProgress("Exporting")
+		files, err := handler.ExportAllEnvFiles(exportDir, projectName, handlers.ExportOptions{
+			Format:        exportFormat,
+			WithExport:    withExport,
+			Order:         exportOrder,
+			Keys:          append(exportKeys, onlyKeys...),
+			IgnoreMissing: ignoreMissing,
+			Exclude:       excludeKeys,
+		}, progress.Update)
+		progress.Finish()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting environments: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		if len(files) == 0 {
+			fmt.Printf("No environments found for project '%s'\n", projectName)
+			return
+		}
+
+		printSuccess("Successfully exported %d environment(s) of project '%s':\n", len(files), projectName)
+		for _, f := range files {
+			fmt.Printf("- %s\n", f)
+		}
+	},
+}
+
+func init() {
+	exportAllCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	exportAllCmd.Flags().StringVar(&exportDir, "dir", "", "Directory to write the files to (required)")
+	exportAllCmd.Flags().StringVar(&exportFormat, "format", handlers.FormatDotenv, "Output format ("+strings.Join(handlers.ExportFormats, ", ")+")")
+	exportAllCmd.Flags().BoolVar(&withExport, "with-export", false, "Prefix each line with 'export ' so the files can be sourced by a shell")
+	exportAllCmd.Flags().StringArrayVar(&exportKeys, "key", nil, "Only export this key, repeat to export several")
+	exportAllCmd.Flags().StringSliceVar(&onlyKeys, "only", nil, "Only export these comma-separated keys")
+	exportAllCmd.Flags().BoolVar(&ignoreMissing, "ignore-missing", false, "Skip --key and --only keys that don't exist instead of failing")
+	exportAllCmd.Flags().StringArrayVar(&excludeKeys, "exclude", nil, "Leave out keys matching this glob pattern (e.g. \"INTERNAL_*\"), repeat for several")
+	exportAllCmd.Flags().StringVar(&exportOrder, "order", handlers.OrderKey, "Order of the variables ("+strings.Join(handlers.ExportOrders, ", ")+")")
+	exportAllCmd.MarkFlagRequired("project")
+	exportAllCmd.MarkFlagRequired("dir")
+	rootCmd.AddCommand(exportAllCmd)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"go-env-cli/internal/pkg/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	olderThan string
+	dryRun    bool
+)
+
+// gcCmd represents the gc command
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Permanently remove old soft-deleted projects and variables",
+	Long: `Permanently remove projects and variables that were soft deleted more than
+--older-than ago (e.g. 90d, 2w, 12h). Variables of removed projects are removed too.
+By default only the counts of what would be removed are printed; use --force to remove them.
+
+Examples:
+  go-env-cli gc --older-than 90d
+  go-env-cli gc --older-than 90d --force`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		age, err := utils.ParseDuration(olderThan)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --older-than: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+		if dryRun && force {
+			fmt.Fprintln(os.Stderr, "Error: --dry-run and --force cannot be used together")
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Remove, or count without --force
+		counts, err := handler.PurgeDeleted(age, !force)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error collecting garbage: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		if !force {
+			fmt.Printf("Would permanently delete %d projects and %d variables deleted more than %s ago\n",
+				counts.Projects, counts.Variables, olderThan)
+			fmt.Println("Run with --force to delete them")
+			return
+		}
+
+		printSuccess("Permanently deleted %d projects and %d variables deleted more than %s ago\n",
+			counts.Projects, counts.Variables, olderThan)
+	},
+}
+
+func init() {
+	gcCmd.Flags().StringVar(&olderThan, "older-than", "90d", "Only remove rows deleted longer ago than this (e.g. 90d, 12h)")
+	gcCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only print what would be removed (the default without --force)")
+	gcCmd.Flags().BoolVarP(&force, "force", "f", false, "Permanently remove the rows")
+	rootCmd.AddCommand(gcCmd)
+}
@@ -2,47 +2,46 @@ package main
 
 import (
 	"fmt"
-	"log"
 	"os"
-	"path/filepath"
 
 	"go-env-cli/config"
+	"go-env-cli/internal/app/handlers"
+	"go-env-cli/internal/app/models"
 	"go-env-cli/internal/pkg/db"
+	"go-env-cli/internal/pkg/logger"
 
 	_ "github.com/lib/pq"
 )
 
 func main() {
-	// Load configuration
+	// Honor the log level used by the CLI
+	if levelName := os.Getenv(logger.EnvVar); levelName != "" {
+		level, err := logger.ParseLevel(levelName)
+		if err != nil {
+			fatalf("%v", err)
+		}
+		logger.SetLevel(level)
+	}
+
+	// Load configuration, from GO_ENV_CLI_CONFIG and GO_ENV_CLI_PROFILE when set
 	fmt.Println("Loading configuration...")
-	cfg, err := config.LoadConfig()
+	cfg, err := config.LoadConfig("", "")
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		fatalf("Failed to load config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		fatalf("Failed to load config: %v", err)
 	}
-	dbConn, err := db.NewDB(db.Config{GO_CLI_DB: cfg.GO_CLI_DB})
+	dbConn, err := db.NewDB(cfg.DB())
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		fatalf("Failed to connect to database: %v", err)
 	}
 	defer dbConn.Close()
 
-	// Get migration files
-	// Try to find migrations directory
-	possiblePaths := []string{
-		filepath.Join(".", "db", "migrations"),
-		filepath.Join("..", "..", "db", "migrations"),
-		filepath.Join(os.Getenv("HOME"), "go-env-cli", "db", "migrations"),
-	}
-
-	var migrationsDir string
-	for _, path := range possiblePaths {
-		if _, err := os.Stat(path); err == nil {
-			migrationsDir = path
-			break
-		}
-	}
-
+	// Find the migrations directory
+	migrationsDir := db.FindMigrationsDir()
 	if migrationsDir == "" {
-		log.Fatalf("Could not find migrations directory in any of the expected locations")
+		fatalf("Could not find migrations directory in any of the expected locations")
 	}
 
 	fmt.Printf("Running migrations from %s...\n", migrationsDir)
@@ -50,13 +49,29 @@ func main() {
 	// Initialize migration manager
 	migrationManager, err := db.NewMigrationManager(dbConn, migrationsDir)
 	if err != nil {
-		log.Fatalf("Failed to initialize migration manager: %v", err)
+		fatalf("Failed to initialize migration manager: %v", err)
 	}
 
 	// Run migrations
 	if err := migrationManager.MigrateUp(); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
+		fatalf("Failed to run migrations: %v", err)
+	}
+
+	// Create the environments commands default to
+	fmt.Println("Seeding default environments...")
+	created, err := handlers.NewEnvHandler(models.NewRepository(dbConn)).SeedEnvironments("")
+	if err != nil {
+		fatalf("Failed to seed default environments: %v", err)
+	}
+	for _, name := range created {
+		fmt.Printf("Created environment %s\n", name)
 	}
 
 	fmt.Println("Database initialization complete!")
 }
+
+// fatalf logs an error and exits
+func fatalf(format string, args ...interface{}) {
+	logger.Errorf(format, args...)
+	os.Exit(1)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go-env-cli/internal/app/handlers"
+
+	"github.com/spf13/cobra"
+)
+
+var importPattern string
+
+// importDirCmd represents the import-dir command
+var importDirCmd = &cobra.Command{
+	Use:   "import-dir [dir]",
+	Short: "Import a directory of .env files, one environment per file",
+	Long: `Import a directory of .env files into a project. Each file is imported into the
+environment named after the file without its extension, so uat.env is imported into
+the uat environment. Missing environments are created.
+
+Examples:
+  go-env-cli import-dir ./envs --project my-project
+  go-env-cli import-dir ./out --project my-project --pattern "s*.env"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := args[0]
+
+		// Validate flags
+		if projectName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
+		}
+
+		// Find the files to import
+		matches, err := filepath.Glob(filepath.Join(dir, importPattern))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --pattern: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		var files []string
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil && info.Mode().IsRegular() {
+				files = append(files, m)
+			}
+		}
+		sort.Strings(files)
+
+		if len(files) == 0 {
+			fmt.Printf("No files matching '%s' found in %s\n", importPattern, dir)
+			return
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Import each file into the environment named after it
+		failed := 0
+		printSuccess("Importing %d file(s) into project '%s':\n", len(files), projectName)
+		for _, f := range files {
+			base := filepath.Base(f)
+			envName := strings.TrimSuffix(base, filepath.Ext(base))
+
+			summary, err := handler.ImportEnvFile(f, projectName, envName, handlers.ImportOptions{AddPrefix: addPrefix})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "- %s -> %s: failed: %v\n", base, envName, err)
+				failed++
+				continue
+			}
+			printSuccess("- %s -> %s: %s\n", base, envName, describeImport(summary))
+		}
+
+		if failed > 0 {
+			fmt.Fprintf(os.Stderr, "Failed to import %d of %d file(s)\n", failed, len(files))
+			os.Exit(1)
+		}
+
+		printSuccess("Successfully imported %d file(s)\n", len(files))
+	},
+}
+
+func init() {
+	importDirCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	importDirCmd.Flags().StringVar(&importPattern, "pattern", "*.env", "Glob pattern selecting the files to import")
+	importDirCmd.Flags().StringVar(&addPrefix, "add-prefix", "", "Prefix added to every imported key (e.g. APP_)")
+	importDirCmd.MarkFlagRequired("project")
+	rootCmd.AddCommand(importDirCmd)
+}
@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"go-env-cli/internal/app/handlers"
+	"go-env-cli/internal/app/models"
+	"go-env-cli/internal/pkg/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// interactiveHelp describes the commands of an interactive session
+const interactiveHelp = `Commands:
+  use [PROJECT [ENV]]  Select the project and environment (shows the current ones without arguments)
+  set KEY VALUE        Set a variable, quote values with spaces
+  get KEY              Print the value of a variable
+  delete KEY           Delete a variable
+  list [PATTERN]       List the variables, optionally only keys matching a pattern
+  history              List the commands of this session
+  !!                   Run the previous command again
+  !N                   Run command N of the history again
+  help                 Show this help
+  exit                 Leave the session (also quit or Ctrl-D)`
+
+// interactiveCmd represents the interactive command
+var interactiveCmd = &cobra.Command{
+	Use:   "interactive",
+	Short: "Edit variables in an interactive session",
+	Long: `Open a prompt that keeps one database connection and a current project and
+environment, so variables can be edited without repeating flags or reconnecting
+for every change. Select the context with "use PROJECT ENV", or start with it
+using --project and --env.
+
+` + interactiveHelp + `
+
+The history is only kept for the session, so values typed into set commands are
+never written to disk.
+
+Examples:
+  go-env-cli interactive
+  go-env-cli interactive --project my-project --env production`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize handler once for the whole session
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		session := &replSession{handler: handler, environment: environmentName}
+		if projectName != "" {
+			if err := session.use(projectName, environmentName); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(exitCode(err))
+			}
+		}
+
+		if err := session.run(os.Stdin); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// replSession is the state of an interactive session
+type replSession struct {
+	handler     *handlers.EnvHandler
+	project     string
+	environment string
+	history     []string
+}
+
+// errExit is returned by execute when the session should end
+var errExit = errors.New("exit")
+
+// run reads commands from in until exit or the end of the input. Errors of single
+// commands are reported and the session goes on.
+func (s *replSession) run(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Print(s.prompt())
+		if !scanner.Scan() {
+			fmt.Println()
+			return scanner.Err()
+		}
+
+		line, err := s.expandHistory(strings.TrimSpace(scanner.Text()))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		s.history = append(s.history, line)
+
+		words, err := utils.SplitCommandLine(line)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			continue
+		}
+		if err := s.execute(words); err != nil {
+			if errors.Is(err, errExit) {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+}
+
+// prompt shows the current project and environment
+func (s *replSession) prompt() string {
+	if s.project == "" {
+		return "go-env-cli> "
+	}
+	return fmt.Sprintf("go-env-cli(%s/%s)> ", s.project, s.environment)
+}
+
+// expandHistory replaces !! and !N with the command they refer to, echoing it like a
+// shell does
+func (s *replSession) expandHistory(line string) (string, error) {
+	if !strings.HasPrefix(line, "!") {
+		return line, nil
+	}
+
+	index := len(s.history)
+	if line != "!!" {
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return "", fmt.Errorf("invalid history reference '%s', use !! or !N", line)
+		}
+		index = n
+	}
+	if index < 1 || index > len(s.history) {
+		return "", fmt.Errorf("no command %s in history", line)
+	}
+
+	expanded := s.history[index-1]
+	fmt.Println(expanded)
+	return expanded, nil
+}
+
+// execute runs a single command of the session
+func (s *replSession) execute(words []string) error {
+	command, args := words[0], words[1:]
+
+	switch command {
+	case "exit", "quit":
+		return errExit
+
+	case "help":
+		fmt.Println(interactiveHelp)
+		return nil
+
+	case "history":
+		for i, line := range s.history {
+			fmt.Printf("%4d  %s\n", i+1, line)
+		}
+		return nil
+
+	case "use":
+		switch len(args) {
+		case 0:
+			if s.project == "" {
+				fmt.Println("No project selected")
+			} else {
+				fmt.Printf("Using project '%s' (%s environment)\n", s.project, s.environment)
+			}
+			return nil
+		case 1:
+			return s.use(args[0], s.environment)
+		case 2:
+			return s.use(args[0], args[1])
+		}
+		return fmt.Errorf("usage: use [PROJECT [ENV]]")
+
+	case "set", "get", "delete", "list":
+		if s.project == "" {
+			return fmt.Errorf("no project selected, run 'use PROJECT [ENV]' first")
+		}
+
+	default:
+		return fmt.Errorf("unknown command '%s', type help for the list of commands", command)
+	}
+
+	switch command {
+	case "set":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: set KEY VALUE")
+		}
+		handlers.WarnIfSecret(args[0], args[1])
+		if err := s.handler.SetEnvVariable(s.project, s.environment, args[0], args[1]); err != nil {
+			return err
+		}
+		printSuccess("Set %s\n", args[0])
+
+	case "get":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: get KEY")
+		}
+		value, err := s.handler.GetEnvVariable(s.project, s.environment, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(value)
+
+	case "delete":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: delete KEY")
+		}
+		if err := s.handler.DeleteEnvVariable(s.project, s.environment, args[0]); err != nil {
+			return err
+		}
+		printSuccess("Deleted %s\n", args[0])
+
+	case "list":
+		if len(args) > 1 {
+			return fmt.Errorf("usage: list [PATTERN]")
+		}
+		var err error
+		var variables []models.EnvVariable
+		if len(args) == 1 {
+			variables, err = s.handler.SearchEnvVariables(s.project, s.environment, args[0])
+		} else {
+			variables, err = s.handler.ListEnvVariables(s.project, s.environment)
+		}
+		if err != nil {
+			return err
+		}
+		if len(variables) == 0 {
+			fmt.Println("No environment variables found")
+		}
+		for _, v := range variables {
+			fmt.Printf("%s=%s\n", colors.Key(v.Key), colors.Value(v.Value))
+		}
+	}
+
+	return nil
+}
+
+// use makes a project and environment the context of the session, checking that the
+// project exists
+func (s *replSession) use(project, environment string) error {
+	if environment == "" {
+		environment = "development"
+	}
+	if _, err := s.handler.ListEnvironments(project); err != nil {
+		return err
+	}
+
+	s.project, s.environment = project, environment
+	return nil
+}
+
+func init() {
+	interactiveCmd.Flags().StringVar(&projectName, "project", "", "Project to start the session with")
+	interactiveCmd.Flags().StringVar(&environmentName, "env", "development", "Environment to start the session with (default: development)")
+	rootCmd.AddCommand(interactiveCmd)
+}
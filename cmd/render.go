@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	templateFile string
+	renderOut    string
+	allowMissing bool
+)
+
+// renderCmd represents the render command
+var renderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Render a Go template with the variables of an environment",
+	Long: `Render a Go text/template file with the variables of a project environment.
+Variables are available as {{ .KEY }} and through the env function as
+{{ env "KEY" }}. Rendering fails when the template uses a key the environment
+doesn't have, unless --allow-missing is set, in which case it renders empty.
+
+The output is written to --out, or to standard output when --out is omitted
+or "-".
+
+Examples:
+  go-env-cli render --project my-project --env prod --template config.tmpl --out config.yaml
+  go-env-cli render --project my-project --template config.tmpl --allow-missing`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
+		}
+		if templateFile == "" {
+			fmt.Fprintln(os.Stderr, "Error: --template flag is required")
+			os.Exit(ExitValidation)
+		}
+
+		text, err := os.ReadFile(templateFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading template: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		name := filepath.Base(templateFile)
+		if renderOut == "" || renderOut == "-" {
+			err = handler.RenderTemplate(os.Stdout, projectName, environmentName, name, string(text), allowMissing)
+		} else {
+			err = handler.RenderTemplateFile(renderOut, projectName, environmentName, name, string(text), allowMissing)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering template: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		if renderOut != "" && renderOut != "-" {
+			printSuccess("Successfully rendered '%s' to '%s'\n", templateFile, renderOut)
+		}
+	},
+}
+
+func init() {
+	renderCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	renderCmd.Flags().StringVar(&environmentName, "env", "development", "Environment name (default: development)")
+	renderCmd.Flags().StringVar(&templateFile, "template", "", "Template file to render (required)")
+	renderCmd.Flags().StringVar(&renderOut, "out", "", "File to write the output to (default: standard output)")
+	renderCmd.Flags().BoolVar(&allowMissing, "allow-missing", false, "Render keys missing from the environment as empty strings instead of failing")
+	renderCmd.MarkFlagRequired("project")
+	renderCmd.MarkFlagRequired("template")
+	rootCmd.AddCommand(renderCmd)
+}
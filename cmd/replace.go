@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"go-env-cli/internal/app/handlers"
+	"go-env-cli/internal/pkg/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchText    string
+	replaceText   string
+	replaceValues bool
+	replaceKeys   bool
+	replaceRegex  bool
+)
+
+// replaceCmd represents the replace command
+var replaceCmd = &cobra.Command{
+	Use:   "replace",
+	Short: "Search and replace text in the values or keys of an environment",
+	Long: `Replace every occurrence of --search with --replace in the values of a project
+environment, e.g. to rotate a hostname or account ID used by many variables. With
+--keys the keys are renamed instead, keeping their values. With --regex, --search is
+a regular expression and --replace may refer to its groups as $1 or ${name}.
+
+The changes are shown and need confirmation, unless --force is given. They are all
+applied in one transaction, so either every variable is changed or none is.
+
+Examples:
+  go-env-cli replace --project test --env prod --search db1.internal --replace db2.internal
+  go-env-cli replace --project test --env prod --search 'acct-[0-9]+' --replace acct-4242 --regex
+  go-env-cli replace --project test --env prod --search LEGACY_ --replace APP_ --keys`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
+		}
+		if replaceValues && replaceKeys {
+			fmt.Fprintln(os.Stderr, "Error: --values and --keys can't be used together")
+			os.Exit(ExitValidation)
+		}
+
+		replacer, err := utils.NewReplacer(searchText, replaceText, replaceRegex)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		replacements, err := handler.PlanReplace(projectName, environmentName, replacer, replaceKeys)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error replacing '%s': %v\n", searchText, err)
+			os.Exit(exitCode(err))
+		}
+
+		if len(replacements) == 0 {
+			fmt.Printf("Nothing matches '%s' in project '%s' (%s environment)\n", searchText, projectName, environmentName)
+			return
+		}
+
+		// Show the changes and confirm unless --force is specified
+		printReplacements(replacements)
+		if !force && !cmd.Flags().Changed("force") {
+			fmt.Printf("Apply %d change(s) to project '%s' (%s environment)? [y/N]: ", len(replacements), projectName, environmentName)
+			var response string
+			fmt.Scanln(&response)
+			if response != "y" && response != "Y" {
+				fmt.Println("Replace cancelled")
+				return
+			}
+		}
+
+		if err := handler.ApplyReplacements(projectName, environmentName, replacements); err != nil {
+			fmt.Fprintf(os.Stderr, "Error replacing '%s': %v\n", searchText, err)
+			os.Exit(exitCode(err))
+		}
+
+		printSuccess("Successfully applied %d change(s) to project '%s' (%s environment)\n",
+			len(replacements), projectName, environmentName)
+	},
+}
+
+// printReplacements prints the changes of a replace, as renamed keys or changed values
+func printReplacements(replacements []handlers.Replacement) {
+	var changes []utils.EnvChange
+	for _, r := range replacements {
+		if r.NewKey != "" {
+			fmt.Println(colors.Changed(fmt.Sprintf("~ %s -> %s", r.Key, r.NewKey)))
+			continue
+		}
+		changes = append(changes, utils.EnvChange{Key: r.Key, OldValue: r.OldValue, NewValue: r.NewValue})
+	}
+	printEnvDiff(utils.EnvDiff{Changed: changes})
+}
+
+func init() {
+	replaceCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	replaceCmd.Flags().StringVar(&environmentName, "env", "development", "Environment name (default: development)")
+	replaceCmd.Flags().StringVar(&searchText, "search", "", "Text to search for (required)")
+	replaceCmd.Flags().StringVar(&replaceText, "replace", "", "Text to replace it with (empty removes it)")
+	replaceCmd.Flags().BoolVar(&replaceValues, "values", false, "Replace in the values (the default)")
+	replaceCmd.Flags().BoolVar(&replaceKeys, "keys", false, "Rename the keys instead of changing the values")
+	replaceCmd.Flags().BoolVar(&replaceRegex, "regex", false, "Treat --search as a regular expression")
+	replaceCmd.Flags().BoolVarP(&force, "force", "f", false, "Apply the changes without confirmation")
+	replaceCmd.MarkFlagRequired("project")
+	replaceCmd.MarkFlagRequired("search")
+	rootCmd.AddCommand(replaceCmd)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// restoreVarCmd represents the restore-var command
+var restoreVarCmd = &cobra.Command{
+	Use:   "restore-var",
+	Short: "Restore a deleted environment variable",
+	Long: `Restore the most recently deleted version of an environment variable. Fails if a
+variable with the same key exists. Use "list --deleted" to see deleted variables.
+
+Example:
+  go-env-cli restore-var --project test --env local --key API_KEY`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
+		}
+		if environmentName == "" {
+			environmentName = "development" // Default to development
+		}
+		if keyName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --key flag is required")
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Restore variable
+		err = handler.RestoreEnvVariable(projectName, environmentName, keyName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error restoring environment variable: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		printSuccess("Successfully restored environment variable '%s' for project '%s' (%s environment)\n",
+			keyName, projectName, environmentName)
+	},
+}
+
+func init() {
+	restoreVarCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	restoreVarCmd.Flags().StringVar(&environmentName, "env", "development", "Environment name (default: development)")
+	restoreVarCmd.Flags().StringVar(&keyName, "key", "", "Environment variable key (required)")
+	restoreVarCmd.MarkFlagRequired("project")
+	restoreVarCmd.MarkFlagRequired("key")
+	rootCmd.AddCommand(restoreVarCmd)
+}
@@ -1,17 +1,26 @@
 package cmd
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"regexp"
 	"runtime"
+	"strings"
 	"syscall"
+	"time"
 
 	"go-env-cli/config"
 	"go-env-cli/internal/app/handlers"
 	"go-env-cli/internal/app/models"
 	"go-env-cli/internal/pkg/db"
+	"go-env-cli/internal/pkg/logger"
+	"go-env-cli/internal/pkg/utils"
 
+	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 )
 
@@ -25,8 +34,85 @@ var (
 	force           bool
 
 	runCommand string
+	cleanEnv   bool
+	keepEnv    []string
+	keyPattern string
+	newKeyName string
+	overwrite  bool
+
+	fromEnvironmentName string
+	toEnvironmentName   string
+
+	withExport   bool
+	showValues   bool
+	defaultValue string
+	tableOutput  bool
+	longOutput   bool
+	projectSort  string
+	projectLimit int
+	inherited    bool
+	parentName   string
+	exportFormat string
+	example      bool
+	placeholder  string
+	secretName   string
+	namespace    string
+
+	projectFilter string
+	keyRegex      string
+	getKeys       []string
+	valuesOnly    bool
+	strictKeys    bool
+	valueFile     string
+	base64Encode  bool
+	base64Decode  bool
+	listDeleted   bool
+	pageLimit     int
+	pageOffset    int
+	keyPrefix     string
+	stripPrefix   bool
+	addPrefix     string
+	ignoreCase    bool
+	upperKeys     bool
+	importFormat  string
+	ssmPath       string
+	projectTags   []string
+	autoCreateEnv bool
+	noWarnSecrets bool
+	exportEnvs    []string
+	noValues      bool
+	updatedWithin string
+	createdWithin string
+	exportKeys    []string
+	onlyKeys      []string
+	ignoreMissing bool
+	excludeKeys   []string
+	noTrim        bool
+	strictDups    bool
+	fetchHeaders  []string
+	fetchTimeout  time.Duration
+	fetchMaxSize  int64
+	importWorkers int
+	encryptTo     string
+	exportOrder   string
+	decrypt       bool
+	identityFile  string
+	writeChecksum bool
+	clip          bool
+	watch         bool
+	watchInterval time.Duration
+
+	logLevel    string
+	quiet       bool
+	noColor     bool
+	debugSQL    bool
+	cfgFile     string
+	profileName string
 )
 
+// colors colors the output of list, diffs and project-details when it goes to a terminal
+var colors utils.Colorizer
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "go-env-cli",
@@ -34,28 +120,70 @@ var rootCmd = &cobra.Command{
 	Long: `go-env-cli is a command-line tool that helps you manage environment variables
 across multiple projects and environments. It stores variables in a PostgreSQL database
 and provides commands for importing/exporting .env files, setting/getting variables,
-and more.`}
+and more.`,
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		colors.Enabled = utils.UseColor(noColor, os.Getenv(utils.NoColorEnvVar), utils.IsTerminal(os.Stdout))
+
+		// The flag takes precedence over the environment variable
+		levelName := os.Getenv(logger.EnvVar)
+		if cmd.Flags().Changed("log-level") {
+			levelName = logLevel
+		}
+		if levelName == "" {
+			return nil
+		}
+
+		level, err := logger.ParseLevel(levelName)
+		if err != nil {
+			return err
+		}
+		logger.SetLevel(level)
+		return nil
+	},
+}
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once.
 func Execute() {
+	registerFlagCompletions(rootCmd)
+
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+
+		// Uncategorized errors returned here come from parsing the command line, such
+		// as a missing required flag or an invalid --log-level
+		code := exitCode(err)
+		if code == ExitError {
+			code = ExitValidation
+		}
+		os.Exit(code)
 	}
 }
 
 func init() {
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only print errors and requested output, not confirmation messages")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output (env: "+utils.NoColorEnvVar+"), which is also off when stdout isn't a terminal")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "Configuration file to use instead of "+config.FileName+" (env: "+config.EnvVar+")")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Database profile from the configuration file (env: "+config.ProfileEnvVar+")")
+	rootCmd.PersistentFlags().BoolVar(&debugSQL, "debug-sql", false, "Log every database query with its duration (config: database.debug_sql)")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "Diagnostics shown on stderr: debug, info, warn or error (env: "+logger.EnvVar+")")
+
 	// Add commands
 	rootCmd.AddCommand(importCmd)
 	rootCmd.AddCommand(exportCmd)
 	rootCmd.AddCommand(listProjectsCmd)
 	rootCmd.AddCommand(searchProjectCmd)
+	rootCmd.AddCommand(searchVarCmd)
 	rootCmd.AddCommand(setEnvCmd)
 	rootCmd.AddCommand(getEnvCmd)
+	rootCmd.AddCommand(hasEnvCmd)
 	rootCmd.AddCommand(deleteEnvCmd)
+	rootCmd.AddCommand(renameEnvCmd)
+	rootCmd.AddCommand(moveEnvCmd)
 	rootCmd.AddCommand(listEnvCmd)
 	rootCmd.AddCommand(softDeleteProjectCmd)
+	rootCmd.AddCommand(updateProjectCmd)
 	rootCmd.AddCommand(environmentCmd)
 	rootCmd.AddCommand(projectDetailsCmd)
 }
@@ -63,18 +191,25 @@ func init() {
 // initHandler creates and initializes the environment handler
 func initHandler() (*handlers.EnvHandler, error) {
 	// Load configuration
-	cfg, err := config.LoadConfig()
+	cfg, err := config.LoadConfig(cfgFile, profileName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load config: %v", err)
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if debugSQL {
+		cfg.Database.DebugSQL = true
+	}
+
+	// Fail on settings that can't work before trying them
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
 
 	// Connect to database
-	dbConn, err := db.NewDB(db.Config{
-		GO_CLI_DB: cfg.GO_CLI_DB,
-	})
+	logger.Debugf("Connecting to database")
+	dbConn, err := db.NewDB(cfg.DB())
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %v", err)
+		return nil, err
 	}
 
 	// Create repository
@@ -90,57 +225,229 @@ func initHandler() (*handlers.EnvHandler, error) {
 var importCmd = &cobra.Command{
 	Use:   "import [file]",
 	Short: "Import environment variables from a .env file",
-	Args:  cobra.ExactArgs(1),
+	Long: `Import environment variables from a .env file.
+Use "-" as the file to read from standard input, or an http:// or https:// URL to
+download the file, e.g. a config template served by an internal endpoint. Add
+request headers such as credentials with --header. The download must finish within
+--timeout and is refused when larger than --max-size bytes.
+
+With --format csv the file needs a header row with key and value columns. An optional
+environment column stores each row in that environment instead of --env.
+
+A key set more than once in the file is reported with a warning and takes its last
+value. Use --strict-duplicates to fail instead, listing every duplicated key with its
+line numbers, so copy-paste mistakes in hand-maintained files never reach the database.
+
+When the import would change the value of existing variables, the changes are shown
+and need confirmation, unless --force is given. Imports from standard input can't be
+confirmed, so they need --force to overwrite values.
+
+Use --decrypt to import a file encrypted by export --encrypt-to. Files encrypted with
+age need the identity file of a recipient given with --identity; gpg files are
+decrypted with the secret keys of the gpg keyring.
+
+All variables are stored in one transaction. For very large files on a remote
+database, --parallel N splits them by key into N batches stored concurrently, each
+in its own transaction, so a failed batch doesn't undo the others. Workers beyond
+the connection pool size (max_open_conns, 5 by default) only wait for a connection.
+
+Examples:
+  go-env-cli import .env --project test --env local
+  go-env-cli import api.env --project test --env local --add-prefix API_
+  go-env-cli import .env --project test --env local --upper
+  go-env-cli import .env --project test --env local --strict-duplicates
+  go-env-cli import vars.csv --project test --env local --format csv
+  cat .env | go-env-cli import - --project test --env local
+  go-env-cli import https://config.internal/test.env --project test --env ci --header "Authorization: Bearer $TOKEN"
+  go-env-cli import generated.env --project test --env local --parallel 4
+  go-env-cli import secrets.env.age --project test --env local --decrypt --identity key.txt`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		filePath := args[0]
 
 		// Validate flags
 		if projectName == "" {
-			fmt.Println("Error: --project flag is required")
-			os.Exit(1)
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
 		}
 		if environmentName == "" {
 			environmentName = "development" // Default to development
 		}
+		if importWorkers < 1 {
+			fmt.Fprintln(os.Stderr, "Error: --parallel must be at least 1")
+			os.Exit(ExitValidation)
+		}
+		if utils.IsURL(filePath) && (fetchTimeout <= 0 || fetchMaxSize <= 0) {
+			fmt.Fprintln(os.Stderr, "Error: --timeout and --max-size must be positive")
+			os.Exit(ExitValidation)
+		}
 
 		// Initialize handler
 		handler, err := initHandler()
 		if err != nil {
-			fmt.Printf("Error initializing: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		importOpts := handlers.ImportOptions{
+			Format:           importFormat,
+			AddPrefix:        addPrefix,
+			Upper:            upperKeys,
+			WarnSecrets:      !noWarnSecrets,
+			NoTrim:           noTrim,
+			StrictDuplicates: strictDups,
+			Parallel:         importWorkers,
 		}
 
-		// Import file
-		err = handler.ImportEnvFile(filePath, projectName, environmentName)
+		// Read the file, standard input when the file is "-", or the body of a URL. The
+		// input is read into memory rather than streamed: decrypting needs all of it,
+		// the preview below and ImportEnv each parse it, and ImportEnv keeps a backup
+		// of it anyway. --max-size bounds how much a URL can make us buffer.
+		source := filePath
+		var content []byte
+		switch {
+		case filePath == "-":
+			source = "stdin"
+			content, err = io.ReadAll(os.Stdin)
+		case utils.IsURL(filePath):
+			source = utils.DisplayURL(filePath)
+			var body io.ReadCloser
+			body, err = utils.FetchURL(filePath, fetchHeaders, fetchTimeout, fetchMaxSize)
+			if err == nil {
+				content, err = io.ReadAll(body)
+				body.Close()
+			}
+		default:
+			content, err = os.ReadFile(filePath)
+		}
 		if err != nil {
-			fmt.Printf("Error importing .env file: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error reading .env file: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Decrypt an age or gpg file before parsing it
+		if decrypt {
+			content, err = utils.Decrypt(content, identityFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error decrypting %s: %v\n", source, err)
+				os.Exit(exitCode(err))
+			}
+		}
+
+		// Show the values that would be overwritten and confirm unless --force is specified
+		if !force {
+			changes, err := handler.PreviewImport(bytes.NewReader(content), projectName, environmentName, importOpts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error importing .env file: %v\n", err)
+				os.Exit(exitCode(err))
+			}
+
+			if len(changes) > 0 {
+				fmt.Printf("Importing %s would overwrite %d existing value(s) in project '%s':\n", source, len(changes), projectName)
+				printEnvDiff(utils.EnvDiff{Changed: changes})
+
+				if filePath == "-" {
+					fmt.Fprintln(os.Stderr, "Error: use --force to overwrite existing values when importing from standard input")
+					os.Exit(ExitValidation)
+				}
+
+				fmt.Print("Overwrite these values? [y/N]: ")
+				var response string
+				fmt.Scanln(&response)
+				if response != "y" && response != "Y" {
+					fmt.Println("Import cancelled")
+					return
+				}
+			}
+		}
+
+		// Import the content
+		progress := newProgress("Importing")
+		importOpts.Progress = progress.Update
+		summary, err := handler.ImportEnv(bytes.NewReader(content), source, projectName, environmentName, importOpts)
+		progress.Finish()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error importing .env file: %v\n", err)
+			os.Exit(exitCode(err))
 		}
 
-		fmt.Printf("Successfully imported environment variables from %s to project '%s' (%s environment)\n",
-			filePath, projectName, environmentName)
+		printSuccess("%s from %s to project '%s' (%s environment)\n",
+			describeImport(summary), source, projectName, environmentName)
 	},
 }
 
+// describeImport summarizes an import, e.g. "Imported 42 variables: 10 new, 5 updated,
+// 27 unchanged"
+func describeImport(summary *models.ImportSummary) string {
+	description := fmt.Sprintf("Imported %d variables: %d new, %d updated, %d unchanged",
+		summary.Total(), summary.Created, summary.Updated, summary.Unchanged)
+	if summary.Skipped > 0 {
+		description += fmt.Sprintf(", %d duplicate(s) skipped", summary.Skipped)
+	}
+	return description
+}
+
 // Export command
 var exportCmd = &cobra.Command{
 	Use:   "export [file]",
 	Short: "Export environment variables to a .env file",
-	Args:  cobra.ExactArgs(1),
+	Long: `Export environment variables to a .env file.
+Use "-" as the file to write to standard output.
+
+Repeat --env to merge several environments from left to right, e.g. a shared base and
+its production overrides. A key present in more than one environment takes its value
+from the last environment listed.
+
+Use --encrypt-to to encrypt the file so it can be shared over insecure channels. age
+public keys (age1...) and SSH public keys are encrypted to with age, anything else,
+such as an email address or key ID, with gpg. The age or gpg binary must be installed.
+
+Use --order to choose the order of the variables: key (the default) sorts them
+alphabetically, created by when they were first set and original keeps the order of
+the file they were last imported from, with variables set since then after them.
+k8s-secret manifests are always sorted by key.
+
+Use --checksum to also write the SHA-256 checksum of the file to FILE.sha256, so the
+verify command can detect a file tampered with or truncated in transit.
+
+Examples:
+  go-env-cli export .env --project test --env local
+  go-env-cli export - --project test --env local > .env
+  go-env-cli export merged.env --project test --env base --env prod
+  go-env-cli export api.env --project test --env local --prefix API_ --strip-prefix
+  go-env-cli export tool.env --project test --env local --only DB_URL,REDIS_URL,PORT
+  go-env-cli export partner.env --project test --env prod --exclude 'INTERNAL_*'
+  go-env-cli export prod.env --project test --env prod --checksum
+  go-env-cli export .env --project test --env local --order original
+  go-env-cli export secrets.env.age --project test --env prod --encrypt-to age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p
+  go-env-cli export - --project test --env prod --format ssm --path-prefix /apps/test/prod | sh`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		filePath := args[0]
 
 		// Validate flags
 		if projectName == "" {
-			fmt.Println("Error: --project flag is required")
-			os.Exit(1)
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
 		}
-		if environmentName == "" {
-			environmentName = "development" // Default to development
+		if len(exportEnvs) == 0 {
+			exportEnvs = []string{"development"} // Default to development
+		}
+		environmentName = exportEnvs[0]
+
+		if stripPrefix && keyPrefix == "" {
+			fmt.Fprintln(os.Stderr, "Error: --strip-prefix requires --prefix")
+			os.Exit(ExitValidation)
+		}
+
+		toStdout := filePath == "-"
+		if toStdout && writeChecksum {
+			fmt.Fprintln(os.Stderr, "Error: --checksum needs a file to export to")
+			os.Exit(ExitValidation)
 		}
 
 		// Check if file exists and confirm overwrite if needed
-		if _, err := os.Stat(filePath); err == nil {
+		if _, err := os.Stat(filePath); err == nil && !toStdout {
 			if !force && !cmd.Flags().Changed("force") {
 				fmt.Printf("File %s already exists. Overwrite? [y/N]: ", filePath)
 				var response string
@@ -155,19 +462,56 @@ var exportCmd = &cobra.Command{
 		// Initialize handler
 		handler, err := initHandler()
 		if err != nil {
-			fmt.Printf("Error initializing: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		opts := handlers.ExportOptions{
+			Format:        exportFormat,
+			WithExport:    withExport,
+			Example:       example,
+			Placeholder:   placeholder,
+			SecretName:    secretName,
+			Namespace:     namespace,
+			Prefix:        keyPrefix,
+			StripPrefix:   stripPrefix,
+			PathPrefix:    ssmPath,
+			NoValues:      noValues,
+			Overlays:      exportEnvs[1:],
+			Keys:          append(exportKeys, onlyKeys...),
+			IgnoreMissing: ignoreMissing,
+			Exclude:       excludeKeys,
+			EncryptTo:     encryptTo,
+			Order:         exportOrder,
+		}
+
+		// Export to standard output, keeping it free of anything but the rendered file
+		if toStdout {
+			if err := handler.ExportEnv(os.Stdout, projectName, environmentName, opts); err != nil {
+				fmt.Fprintf(os.Stderr, "Error exporting environment variables: %v\n", err)
+				os.Exit(exitCode(err))
+			}
+			return
 		}
 
 		// Export to file
-		err = handler.ExportEnvFile(filePath, projectName, environmentName)
+		err = handler.ExportEnvFile(filePath, projectName, environmentName, opts)
 		if err != nil {
-			fmt.Printf("Error exporting to .env file: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error exporting to .env file: %v\n", err)
+			os.Exit(exitCode(err))
 		}
 
-		fmt.Printf("Successfully exported environment variables from project '%s' (%s environment) to %s\n",
-			projectName, environmentName, filePath)
+		printSuccess("Successfully exported environment variables from project '%s' (%s environment) to %s\n",
+			projectName, strings.Join(exportEnvs, " + "), filePath)
+
+		// Record the checksum for verify
+		if writeChecksum {
+			if _, err := utils.WriteChecksumFile(filePath); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing checksum: %v\n", err)
+				os.Exit(exitCode(err))
+			}
+			printSuccess("Wrote checksum to %s\n", filePath+utils.ChecksumExtension)
+		}
 	},
 }
 
@@ -175,19 +519,31 @@ var exportCmd = &cobra.Command{
 var listProjectsCmd = &cobra.Command{
 	Use:   "list-projects",
 	Short: "List all projects",
+	Long: `List all projects.
+
+Examples:
+  go-env-cli list-projects
+  go-env-cli list-projects --sort updated --limit 10
+  go-env-cli list-projects --filter billing --table
+  go-env-cli list-projects --tag team:payments --tag tier:critical`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Initialize handler
 		handler, err := initHandler()
 		if err != nil {
-			fmt.Printf("Error initializing: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
 		}
 
 		// Get projects
-		projects, err := handler.ListProjects()
+		projects, err := handler.QueryProjects(models.ProjectQuery{
+			Pattern: projectFilter,
+			Sort:    projectSort,
+			Limit:   projectLimit,
+			Tags:    projectTags,
+		})
 		if err != nil {
-			fmt.Printf("Error listing projects: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error listing projects: %v\n", err)
+			os.Exit(exitCode(err))
 		}
 
 		// Display projects
@@ -196,6 +552,20 @@ var listProjectsCmd = &cobra.Command{
 			return
 		}
 
+		if tableOutput {
+			// Count environments per project for the table
+			environmentCounts := make(map[uuid.UUID]int, len(projects))
+			for _, p := range projects {
+				environments, err := handler.GetEnvironmentsForProject(p.Name)
+				if err == nil {
+					environmentCounts[p.ID] = len(environments)
+				}
+			}
+
+			renderProjectsTable(os.Stdout, projects, environmentCounts)
+			return
+		}
+
 		fmt.Println("Projects:")
 		fmt.Println("=========")
 		for _, p := range projects {
@@ -228,15 +598,15 @@ var searchProjectCmd = &cobra.Command{
 		// Initialize handler
 		handler, err := initHandler()
 		if err != nil {
-			fmt.Printf("Error initializing: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
 		}
 
 		// Search projects
 		projects, err := handler.SearchProjects(pattern)
 		if err != nil {
-			fmt.Printf("Error searching projects: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error searching projects: %v\n", err)
+			os.Exit(exitCode(err))
 		}
 
 		// Display projects
@@ -253,40 +623,143 @@ var searchProjectCmd = &cobra.Command{
 	},
 }
 
+// Search env variable command
+var searchVarCmd = &cobra.Command{
+	Use:   "search-var [pattern]",
+	Short: "Search for environment variables by key pattern across all projects",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pattern := args[0]
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Search variables
+		matches, err := handler.SearchEnvVariablesGlobal(pattern)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error searching environment variables: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Display matches
+		if len(matches) == 0 {
+			fmt.Printf("No environment variables found matching '%s'\n", pattern)
+			return
+		}
+
+		fmt.Printf("Environment variables matching '%s':\n", pattern)
+		fmt.Println("===================================")
+		for _, m := range matches {
+			if showValues {
+				fmt.Printf("- %s (%s): %s=%s\n", m.ProjectName, m.EnvironmentName, m.Key, m.Value)
+			} else {
+				fmt.Printf("- %s (%s): %s\n", m.ProjectName, m.EnvironmentName, m.Key)
+			}
+		}
+	},
+}
+
 // Set env variable command
 var setEnvCmd = &cobra.Command{
 	Use:   "set",
 	Short: "Set an environment variable",
+	Long: `Set an environment variable.
+Use --value-file to store the exact contents of a file instead of --value, including
+newlines, which avoids shell quoting of multiline secrets. Use "-" to read standard
+input. Add --base64 to store the value base64-encoded, for binary content such as keystores.
+The environment must exist unless --auto-create-env is given; import always creates it.
+A warning is printed when the value looks like a real credential, such as an AWS access
+key or a private key, unless --no-warn-secrets is given.
+
+Examples:
+  go-env-cli set --project test --env local --key PORT --value 8080
+  go-env-cli set --project test --env prod --key PORT --value 80 --auto-create-env
+  go-env-cli set --project test --env local --key PRIVATE_KEY --value-file key.pem
+  vault read -field=key secret/app | go-env-cli set --project test --env local --key API_KEY --value-file -
+  go-env-cli set --project test --env local --key KEYSTORE --value-file keystore.p12 --base64`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Validate flags
 		if projectName == "" {
-			fmt.Println("Error: --project flag is required")
-			os.Exit(1)
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
 		}
 		if environmentName == "" {
 			environmentName = "development" // Default to development
 		}
 		if keyName == "" {
-			fmt.Println("Error: --key flag is required")
-			os.Exit(1)
+			fmt.Fprintln(os.Stderr, "Error: --key flag is required")
+			os.Exit(ExitValidation)
+		}
+		// Read the value from a file, or standard input for "-"
+		value := keyValue
+		if valueFile != "" {
+			var content []byte
+			var err error
+			if valueFile == "-" {
+				content, err = io.ReadAll(os.Stdin)
+			} else {
+				content, err = os.ReadFile(valueFile)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading value file: %v\n", err)
+				os.Exit(exitCode(err))
+			}
+			value = string(content)
+		}
+		if !noWarnSecrets {
+			handlers.WarnIfSecret(keyName, value)
+		}
+		if base64Encode {
+			value = utils.EncodeBase64([]byte(value))
 		}
 
 		// Initialize handler
 		handler, err := initHandler()
 		if err != nil {
-			fmt.Printf("Error initializing: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Create the environment on first use
+		if autoCreateEnv {
+			created, err := handler.EnsureEnvironment(projectName, environmentName, fmt.Sprintf("Environment created for project: %s", projectName))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error creating environment: %v\n", err)
+				os.Exit(exitCode(err))
+			}
+			if created {
+				printSuccess("Created environment '%s'\n", environmentName)
+			}
+		}
+
+		// Update the existing variable whatever the case of its key
+		if ignoreCase {
+			keyName, err = handler.ResolveKey(projectName, environmentName, keyName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting environment variable: %v\n", err)
+				os.Exit(exitCode(err))
+			}
 		}
 
 		// Set variable
-		err = handler.SetEnvVariable(projectName, environmentName, keyName, keyValue)
+		err = handler.SetEnvVariable(projectName, environmentName, keyName, value)
 		if err != nil {
-			fmt.Printf("Error setting environment variable: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error setting environment variable: %v\n", err)
+			os.Exit(exitCode(err))
 		}
 
-		fmt.Printf("Successfully set %s=%s for project '%s' (%s environment)\n",
-			keyName, keyValue, projectName, environmentName)
+		if valueFile != "" || base64Encode {
+			// Don't echo file contents or encoded values
+			printSuccess("Successfully set %s (%d bytes) for project '%s' (%s environment)\n",
+				keyName, len(value), projectName, environmentName)
+		} else {
+			printSuccess("Successfully set %s=%s for project '%s' (%s environment)\n",
+				keyName, value, projectName, environmentName)
+		}
 	},
 }
 
@@ -294,32 +767,107 @@ var setEnvCmd = &cobra.Command{
 var getEnvCmd = &cobra.Command{
 	Use:   "get",
 	Short: "Get an environment variable",
+	Long: `Get an environment variable and print its value.
+Use --default to print a fallback value instead of failing when the variable doesn't exist.
+
+Use --base64-decode to decode a value stored with "set --base64" and write the raw bytes.
+
+Repeat --key to get several variables at once, printed as KEY=value lines in the order
+given (or just the values with --values-only). Missing keys are reported on stderr while
+the others are still printed, unless --strict is set, which fails without printing anything.
+
+Use --ignore-case to match keys regardless of case, e.g. db_url finds DB_URL. It fails if
+several variables match.
+
+Use --clip to copy the value to the clipboard instead of printing it, so a secret
+doesn't linger in the terminal scrollback. It uses pbcopy on macOS, clip on Windows and
+wl-copy, xclip or xsel elsewhere, and prints the value with a warning when none is found.
+
+Examples:
+  go-env-cli get --project test --env local --key PORT
+  go-env-cli get --project test --env local --key PORT --default 8080
+  go-env-cli get --project test --env local --key DB_HOST --key DB_PORT --key DB_NAME
+  go-env-cli get --project test --env local --key db_url --ignore-case
+  go-env-cli get --project test --env prod --key API_KEY --clip
+  go-env-cli get --project test --env local --key KEYSTORE --base64-decode > keystore.p12`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Validate flags
 		if projectName == "" {
-			fmt.Println("Error: --project flag is required")
-			os.Exit(1)
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
 		}
 		if environmentName == "" {
 			environmentName = "development" // Default to development
 		}
-		if keyName == "" {
-			fmt.Println("Error: --key flag is required")
-			os.Exit(1)
+		if len(getKeys) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: --key flag is required")
+			os.Exit(ExitValidation)
+		}
+		if len(getKeys) > 1 && base64Decode {
+			fmt.Fprintln(os.Stderr, "Error: --base64-decode can only be used with a single --key")
+			os.Exit(ExitValidation)
+		}
+		if clip && (len(getKeys) > 1 || base64Decode) {
+			fmt.Fprintln(os.Stderr, "Error: --clip can only be used with a single --key, without --base64-decode")
+			os.Exit(ExitValidation)
 		}
 
 		// Initialize handler
 		handler, err := initHandler()
 		if err != nil {
-			fmt.Printf("Error initializing: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Look keys up whatever their case
+		if ignoreCase {
+			for i, key := range getKeys {
+				getKeys[i], err = handler.ResolveKey(projectName, environmentName, key)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error getting environment variable: %v\n", err)
+					os.Exit(exitCode(err))
+				}
+			}
 		}
 
-		// Get variable
-		value, err := handler.GetEnvVariable(projectName, environmentName, keyName)
+		if len(getKeys) > 1 {
+			getEnvVariables(cmd, handler)
+			return
+		}
+
+		// Get variable, falling back to --default when it doesn't exist
+		value, err := handler.GetEnvVariable(projectName, environmentName, getKeys[0])
+		if errors.Is(err, models.ErrVariableNotFound) && cmd.Flags().Changed("default") {
+			value, err = defaultValue, nil
+		}
 		if err != nil {
-			fmt.Printf("Error getting environment variable: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error getting environment variable: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Write the decoded bytes exactly, without a trailing newline
+		if base64Decode {
+			decoded, err := utils.DecodeBase64(value)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error decoding value: %v\n", err)
+				os.Exit(exitCode(err))
+			}
+			os.Stdout.Write(decoded)
+			return
+		}
+
+		// Copy the value so it doesn't end up in the terminal scrollback
+		if clip {
+			err := copyToClipboard(value)
+			if err == nil {
+				printSuccess("Copied %s to the clipboard\n", getKeys[0])
+				return
+			}
+			if !errors.Is(err, errNoClipboard) {
+				fmt.Fprintf(os.Stderr, "Error copying to the clipboard: %v\n", err)
+				os.Exit(exitCode(err))
+			}
+			logger.Warnf("No clipboard tool found (pbcopy, clip, wl-copy, xclip or xsel), printing the value instead")
 		}
 
 		// Just print the value (for piping to other commands)
@@ -327,140 +875,615 @@ var getEnvCmd = &cobra.Command{
 	},
 }
 
+// getEnvVariables prints the variables requested with a repeated --key flag
+func getEnvVariables(cmd *cobra.Command, handler *handlers.EnvHandler) {
+	values, err := handler.GetEnvVariables(projectName, environmentName, getKeys)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting environment variables: %v\n", err)
+		os.Exit(exitCode(err))
+	}
+
+	// Report missing keys, falling back to --default when given
+	var missing []string
+	for _, key := range getKeys {
+		if _, ok := values[key]; ok {
+			continue
+		}
+		if cmd.Flags().Changed("default") {
+			values[key] = defaultValue
+			continue
+		}
+		missing = append(missing, key)
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(os.Stderr, "Environment variables not found: %s\n", strings.Join(missing, ", "))
+		if strictKeys {
+			os.Exit(ExitNotFound)
+		}
+	}
+
+	for _, key := range getKeys {
+		value, ok := values[key]
+		if !ok {
+			continue
+		}
+		if valuesOnly {
+			fmt.Println(value)
+		} else {
+			fmt.Printf("%s=%s\n", key, value)
+		}
+	}
+}
+
+// listDeletedEnvVariables prints the soft-deleted variables of the selected environment
+func listDeletedEnvVariables(handler *handlers.EnvHandler) {
+	variables, err := handler.ListDeletedEnvVariables(projectName, environmentName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing deleted environment variables: %v\n", err)
+		os.Exit(exitCode(err))
+	}
+
+	if len(variables) == 0 {
+		fmt.Printf("No deleted environment variables found for project '%s' (%s environment)\n",
+			projectName, environmentName)
+		return
+	}
+
+	if tableOutput {
+		renderDeletedVariablesTable(os.Stdout, variables)
+		return
+	}
+
+	fmt.Printf("Deleted environment variables for project '%s' (%s environment):\n",
+		projectName, environmentName)
+	fmt.Println("=================================================")
+	for _, v := range variables {
+		fmt.Printf("%s=%s  # deleted %s\n", v.Key, v.Value, v.DeletedAt.Format(timestampFormat))
+	}
+}
+
+// Has env variable command
+var hasEnvCmd = &cobra.Command{
+	Use:   "has",
+	Short: "Check whether an environment variable exists",
+	Long: `Check whether an environment variable exists without printing anything.
+Exits with status 0 if the variable exists and 1 if it does not. Other failures exit
+with the status of their category, e.g. 2 when the project or environment doesn't
+exist and 4 when the database can't be reached.
+
+Examples:
+  if go-env-cli has --project test --env local --key API_KEY; then echo "set"; fi`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
+		}
+		if environmentName == "" {
+			environmentName = "development" // Default to development
+		}
+		if keyName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --key flag is required")
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Check variable
+		exists, err := handler.HasEnvVariable(projectName, environmentName, keyName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking environment variable: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// An absent key isn't an error, so scripts can test it with if
+		if !exists {
+			os.Exit(1)
+		}
+	},
+}
+
 // Delete env variable command
 var deleteEnvCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete an environment variable",
+	Long: `Delete an environment variable by key, or every variable whose key matches a pattern.
+Patterns use "*" to match any run of characters and "?" to match a single character.
+
+Examples:
+  go-env-cli delete --project test --env local --key API_KEY
+  go-env-cli delete --project test --env local --pattern "OLD_*"`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Validate flags
 		if projectName == "" {
-			fmt.Println("Error: --project flag is required")
-			os.Exit(1)
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
 		}
 		if environmentName == "" {
 			environmentName = "development" // Default to development
 		}
-		if keyName == "" {
-			fmt.Println("Error: --key flag is required")
-			os.Exit(1)
+		if keyName == "" && keyPattern == "" {
+			fmt.Fprintln(os.Stderr, "Error: --key or --pattern flag is required")
+			os.Exit(ExitValidation)
+		}
+		if keyName != "" && keyPattern != "" {
+			fmt.Fprintln(os.Stderr, "Error: --key and --pattern cannot be used together")
+			os.Exit(ExitValidation)
+		}
+
+		// Confirm pattern deletion unless --force is specified
+		if keyPattern != "" && !force && !cmd.Flags().Changed("force") {
+			fmt.Printf("Are you sure you want to delete all variables matching '%s' from project '%s' (%s environment)? [y/N]: ",
+				keyPattern, projectName, environmentName)
+			var response string
+			fmt.Scanln(&response)
+			if response != "y" && response != "Y" {
+				fmt.Println("Delete cancelled")
+				return
+			}
 		}
 
 		// Initialize handler
 		handler, err := initHandler()
 		if err != nil {
-			fmt.Printf("Error initializing: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		if keyPattern != "" {
+			// Delete variables matching the pattern
+			count, err := handler.DeleteEnvVariablesByPattern(projectName, environmentName, keyPattern)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error deleting environment variables: %v\n", err)
+				os.Exit(exitCode(err))
+			}
+
+			printSuccess("Successfully deleted %d environment variable(s) matching '%s' from project '%s' (%s environment)\n",
+				count, keyPattern, projectName, environmentName)
+			return
+		}
+
+		// Delete the variable whatever the case of its key
+		if ignoreCase {
+			keyName, err = handler.ResolveKey(projectName, environmentName, keyName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error deleting environment variable: %v\n", err)
+				os.Exit(exitCode(err))
+			}
 		}
 
 		// Delete variable
 		err = handler.DeleteEnvVariable(projectName, environmentName, keyName)
 		if err != nil {
-			fmt.Printf("Error deleting environment variable: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error deleting environment variable: %v\n", err)
+			os.Exit(exitCode(err))
 		}
 
-		fmt.Printf("Successfully deleted environment variable '%s' from project '%s' (%s environment)\n",
+		printSuccess("Successfully deleted environment variable '%s' from project '%s' (%s environment)\n",
 			keyName, projectName, environmentName)
 	},
 }
 
+// Rename env variable command
+var renameEnvCmd = &cobra.Command{
+	Use:   "rename-var",
+	Short: "Rename an environment variable key",
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
+		}
+		if environmentName == "" {
+			environmentName = "development" // Default to development
+		}
+		if keyName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --key flag is required")
+			os.Exit(ExitValidation)
+		}
+		if newKeyName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --new-key flag is required")
+			os.Exit(ExitValidation)
+		}
+		if newKeyName == keyName {
+			fmt.Fprintln(os.Stderr, "Error: --new-key must differ from --key")
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Rename variable
+		err = handler.RenameEnvVariable(projectName, environmentName, keyName, newKeyName, overwrite)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error renaming environment variable: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		printSuccess("Successfully renamed environment variable '%s' to '%s' in project '%s' (%s environment)\n",
+			keyName, newKeyName, projectName, environmentName)
+	},
+}
+
+// Move env variable command
+var moveEnvCmd = &cobra.Command{
+	Use:   "move-var",
+	Short: "Move an environment variable to another environment",
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
+		}
+		if fromEnvironmentName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --from flag is required")
+			os.Exit(ExitValidation)
+		}
+		if toEnvironmentName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --to flag is required")
+			os.Exit(ExitValidation)
+		}
+		if keyName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --key flag is required")
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Move variable
+		err = handler.MoveEnvVariable(projectName, fromEnvironmentName, toEnvironmentName, keyName, overwrite)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error moving environment variable: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		printSuccess("Successfully moved environment variable '%s' in project '%s' from %s to %s environment\n",
+			keyName, projectName, fromEnvironmentName, toEnvironmentName)
+	},
+}
+
 // List env variables command
 var listEnvCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all environment variables for a project",
 	Long: `List all environment variables for a project.
-Use --run flag to execute a command with the environment variables loaded.
+To execute a command with the environment variables loaded, pass the command and its
+arguments after "--". The command is run directly without a shell, so arguments are
+passed through exactly as given. The --run flag runs a command string through the
+system shell instead, which is convenient for pipelines but subject to shell quoting.
+Use --clean to run the command with only the project's variables, optionally keeping
+selected variables of the current environment with --keep.
+Use --long to also show when each variable was last updated, and with --inherited
+which environment it comes from. Use --deleted to list soft-deleted variables, which
+restore-var can bring back. Use --limit and --offset to page through many variables.
+Use --since to only list variables updated within a window such as 7d or 24h, and
+--created-since to only list those created within it.
 
 Examples:
   go-env-cli list --project test --env local
-  go-env-cli list --project test --env local --run "make run"
-  go-env-cli list --project test --env local --run "node server.js"`,
+  go-env-cli list --project test --env local --long --table
+  go-env-cli list --project test --env local --regex '^(AWS|GCP)_'
+  go-env-cli list --project test --env local --limit 50 --offset 100
+  go-env-cli list --project test --env prod --since 7d --long
+  go-env-cli list --project test --env local -- make run
+  go-env-cli list --project test --env local -- node server.js --port 3000
+  go-env-cli list --project test --env local --run "make run | tee run.log"
+  go-env-cli list --project test --env local --clean --keep PATH,HOME -- ./server
+  go-env-cli list --project test --env local --watch -- go run ./server`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Validate flags
 		if projectName == "" {
-			fmt.Println("Error: --project flag is required")
-			os.Exit(1)
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
+		}
+
+		// Arguments after "--" form the command to run
+		var commandArgs []string
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			commandArgs = args[dash:]
+		} else if len(args) > 0 {
+			fmt.Fprintln(os.Stderr, "Error: pass the command to run after \"--\"")
+			os.Exit(ExitValidation)
+		}
+		if runCommand != "" && len(commandArgs) > 0 {
+			fmt.Fprintln(os.Stderr, "Error: --run cannot be combined with a command after \"--\"")
+			os.Exit(ExitValidation)
+		}
+		running := runCommand != "" || len(commandArgs) > 0
+		if watch && !running {
+			fmt.Fprintln(os.Stderr, "Error: --watch requires a command to run")
+			os.Exit(ExitValidation)
+		}
+		if watchInterval <= 0 {
+			fmt.Fprintln(os.Stderr, "Error: --watch-interval must be positive")
+			os.Exit(ExitValidation)
+		}
+
+		// Compile the key regex before connecting
+		var re *regexp.Regexp
+		if keyRegex != "" {
+			if keyName != "" {
+				fmt.Fprintln(os.Stderr, "Error: --regex cannot be combined with --filter")
+				os.Exit(ExitValidation)
+			}
+
+			var err error
+			re, err = regexp.Compile(keyRegex)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid --regex: %v\n", err)
+				os.Exit(exitCode(err))
+			}
 		}
+
 		if environmentName == "" {
 			environmentName = "development" // Default to development
 		}
 
+		// Turn the --since and --created-since windows into cutoff times
+		var updatedSince, createdSince time.Time
+		for _, window := range []struct {
+			flag, value string
+			cutoff      *time.Time
+		}{
+			{"--since", updatedWithin, &updatedSince},
+			{"--created-since", createdWithin, &createdSince},
+		} {
+			if window.value == "" {
+				continue
+			}
+			age, err := utils.ParseDuration(window.value)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid %s: %v\n", window.flag, err)
+				os.Exit(ExitValidation)
+			}
+			*window.cutoff = time.Now().Add(-age)
+		}
+		changedSince := !updatedSince.IsZero() || !createdSince.IsZero()
+
+		paginated := cmd.Flags().Changed("limit") || cmd.Flags().Changed("offset")
+		if paginated && running {
+			fmt.Fprintln(os.Stderr, "Error: --limit and --offset cannot be combined with running a command")
+			os.Exit(ExitValidation)
+		}
+		if pageLimit < 0 || pageOffset < 0 {
+			fmt.Fprintln(os.Stderr, "Error: --limit and --offset must not be negative")
+			os.Exit(ExitValidation)
+		}
+
+		if listDeleted && running {
+			fmt.Fprintln(os.Stderr, "Error: --deleted cannot be combined with running a command")
+			os.Exit(ExitValidation)
+		}
+
 		// Initialize handler
 		handler, err := initHandler()
 		if err != nil {
-			fmt.Printf("Error initializing: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
 		}
 
-		// Get variables
-		var variables []models.EnvVariable
-		if keyName != "" {
-			// Search by pattern
-			variables, err = handler.SearchEnvVariables(projectName, environmentName, keyName)
-		} else {
-			// List all
-			variables, err = handler.ListEnvVariables(projectName, environmentName)
+		if listDeleted {
+			listDeletedEnvVariables(handler)
+			return
+		}
+
+		// Get variables, again on every check when watching
+		loadVariables := func() (variables []models.EnvVariable, total int, err error) {
+			total = -1
+			if inherited {
+				// Merge variables inherited from parent environments
+				variables, err = handler.ListEnvVariablesInherited(projectName, environmentName)
+				if err == nil && keyName != "" {
+					variables = handlers.FilterEnvVariables(variables, keyName)
+				}
+			} else if keyName != "" {
+				// Search by pattern
+				variables, err = handler.SearchEnvVariables(projectName, environmentName, keyName)
+			} else if changedSince {
+				// Only variables changed within the window
+				variables, err = handler.ListEnvVariablesSince(projectName, environmentName, updatedSince, createdSince)
+			} else if paginated && re == nil {
+				// Let the database page through the variables
+				variables, total, err = handler.ListEnvVariablesPage(projectName, environmentName, pageLimit, pageOffset)
+			} else {
+				// List all
+				variables, err = handler.ListEnvVariables(projectName, environmentName)
+			}
+			if err != nil {
+				return nil, 0, err
+			}
+
+			if re != nil {
+				variables = handlers.FilterEnvVariablesRegex(variables, re)
+			}
+			if changedSince && (inherited || keyName != "") {
+				variables = handlers.FilterEnvVariablesSince(variables, updatedSince, createdSince)
+			}
+
+			// Page through filtered variables
+			if paginated && total < 0 {
+				total = len(variables)
+				variables = handlers.PageEnvVariables(variables, pageLimit, pageOffset)
+			}
+
+			return variables, total, nil
 		}
 
+		variables, total, err := loadVariables()
 		if err != nil {
-			fmt.Printf("Error listing environment variables: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error listing environment variables: %v\n", err)
+			os.Exit(exitCode(err))
 		}
 
 		// Display variables
 		if len(variables) == 0 {
-			fmt.Printf("No environment variables found for project '%s' (%s environment)\n",
-				projectName, environmentName)
+			if paginated {
+				fmt.Printf("No environment variables found for project '%s' (%s environment) at offset %d of %d\n",
+					projectName, environmentName, pageOffset, total)
+			} else {
+				fmt.Printf("No environment variables found for project '%s' (%s environment)\n",
+					projectName, environmentName)
+			}
 			return
 		}
+		if paginated {
+			// Printed once the variables have been listed
+			defer fmt.Printf("Showing %d-%d of %d variables\n", pageOffset+1, pageOffset+len(variables), total)
+		}
 
-		if runCommand == "" {
-			fmt.Printf("Environment variables for project '%s' (%s environment):\n",
-				projectName, environmentName)
+		// Environment names of inherited variables, shown by --long
+		var sources map[uuid.UUID]string
+		if !running && longOutput && inherited {
+			environments, err := handler.ListEnvironments(projectName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error listing environments: %v\n", err)
+				os.Exit(exitCode(err))
+			}
+			sources = make(map[uuid.UUID]string, len(environments))
+			for _, e := range environments {
+				sources[e.ID] = e.Name
+			}
+		}
+
+		if !running && tableOutput {
+			if longOutput {
+				renderVariablesLongTable(os.Stdout, variables, sources)
+			} else {
+				renderVariablesTable(os.Stdout, variables)
+			}
+			return
+		}
+
+		if !running {
+			fmt.Println(colors.Heading(fmt.Sprintf("Environment variables for project '%s' (%s environment):",
+				projectName, environmentName)))
 			fmt.Println("=================================================")
 			for _, v := range variables {
-				fmt.Printf("%s=%s\n", v.Key, v.Value)
+				switch {
+				case longOutput && sources != nil:
+					fmt.Printf("%s=%s  # updated %s, from %s\n", colors.Key(v.Key), colors.Value(v.Value), v.UpdatedAt.Format(timestampFormat), sources[v.EnvironmentID])
+				case longOutput:
+					fmt.Printf("%s=%s  # updated %s\n", colors.Key(v.Key), colors.Value(v.Value), v.UpdatedAt.Format(timestampFormat))
+				default:
+					fmt.Printf("%s=%s\n", colors.Key(v.Key), colors.Value(v.Value))
+				}
 			}
 			return
 		}
 
-		fmt.Printf("Running command with environment variables from project '%s' (%s environment):\n",
-			projectName, environmentName)
-		fmt.Printf("Command: %s\n", runCommand)
-		fmt.Println("=================================================")
+		if !quiet {
+			fmt.Printf("Running command with environment variables from project '%s' (%s environment):\n",
+				projectName, environmentName)
+			if runCommand != "" {
+				fmt.Printf("Command: %s\n", runCommand)
+			} else {
+				fmt.Printf("Command: %s\n", strings.Join(commandArgs, " "))
+			}
+			fmt.Println("=================================================")
+		}
+
+		// Restart the command whenever the variables change
+		if watch {
+			newCmd := func() *exec.Cmd {
+				if runCommand != "" {
+					return shellCommand(runCommand)
+				}
+				return exec.Command(commandArgs[0], commandArgs[1:]...)
+			}
+			reload := func() ([]models.EnvVariable, error) {
+				variables, _, err := loadVariables()
+				return variables, err
+			}
 
-		err = runCommandWithEnv(runCommand, variables)
+			if err := watchCommand(newCmd, reload, variables, watchInterval); err != nil {
+				fmt.Fprintf(os.Stderr, "Error running command: %v\n", err)
+				os.Exit(exitCode(err))
+			}
+			return
+		}
+
+		env := commandEnv(variables, cleanEnv, keepEnv)
+		if runCommand != "" {
+			err = runCommandWithEnv(runCommand, env)
+		} else {
+			err = runArgsWithEnv(commandArgs, env)
+		}
 		if err != nil {
-			fmt.Printf("Error running command: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error running command: %v\n", err)
+			os.Exit(exitCode(err))
 		}
 	},
 }
 
-// runCommandWithEnv runs a command with the provided environment variables
-func runCommandWithEnv(command string, variables []models.EnvVariable) error {
-	if command == "" {
-		return fmt.Errorf("empty command")
+// commandEnv builds the environment for a command run with the provided variables.
+// Normally the variables are added to the current environment; when clean is true the
+// command only sees the variables plus the current values of the names listed in keep.
+func commandEnv(variables []models.EnvVariable, clean bool, keep []string) []string {
+	var env []string
+	if clean {
+		// Start from an empty environment, keeping only whitelisted variables
+		for _, name := range keep {
+			if value, ok := os.LookupEnv(name); ok {
+				env = append(env, fmt.Sprintf("%s=%s", name, value))
+			}
+		}
+	} else {
+		env = os.Environ() // Get current environment
 	}
 
-	// Prepare environment variables
-	env := os.Environ() // Get current environment
-
 	// Add our variables
 	for _, v := range variables {
 		env = append(env, fmt.Sprintf("%s=%s", v.Key, v.Value))
 	}
 
-	// Use shell to execute the command (รองรับ complex commands)
-	var cmd *exec.Cmd
+	return env
+}
+
+// runCommandWithEnv runs a command string through the system shell with the provided environment
+func runCommandWithEnv(command string, env []string) error {
+	if command == "" {
+		return fmt.Errorf("empty command")
+	}
 
+	return runWithEnv(shellCommand(command), env)
+}
+
+// shellCommand builds a command running a command string through the system shell
+func shellCommand(command string) *exec.Cmd {
+	// Use shell to execute the command (รองรับ complex commands)
 	// ตรวจสอบ OS เพื่อใช้ shell ที่เหมาะสม
 	if isWindows() {
-		cmd = exec.Command("cmd", "/C", command)
-	} else {
-		cmd = exec.Command("sh", "-c", command)
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+// runArgsWithEnv runs a program directly, without a shell, with the provided environment
+func runArgsWithEnv(args []string, env []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("empty command")
 	}
 
+	return runWithEnv(exec.Command(args[0], args[1:]...), env)
+}
+
+// runWithEnv runs cmd attached to the terminal with the provided environment. If the
+// command exits with a non-zero status, the CLI exits with the same status.
+func runWithEnv(cmd *exec.Cmd, env []string) error {
 	// Set environment
 	cmd.Env = env
 	cmd.Stdout = os.Stdout
@@ -468,6 +1491,7 @@ func runCommandWithEnv(command string, variables []models.EnvVariable) error {
 	cmd.Stdin = os.Stdin
 
 	// Run command
+	logger.Debugf("Running %s with %d environment variables", strings.Join(cmd.Args, " "), len(env))
 	err := cmd.Run()
 	if err != nil {
 		// Check if it's an exit error
@@ -482,6 +1506,19 @@ func runCommandWithEnv(command string, variables []models.EnvVariable) error {
 	return nil
 }
 
+// printSuccess prints a confirmation message unless --quiet is set
+func printSuccess(format string, args ...interface{}) {
+	if !quiet {
+		fmt.Printf(format, args...)
+	}
+}
+
+// newProgress creates a progress line for a long operation, shown on stdout unless
+// --quiet is set or stdout isn't a terminal
+func newProgress(label string) *utils.Progress {
+	return utils.NewProgress(os.Stdout, label, !quiet && utils.IsTerminal(os.Stdout))
+}
+
 // Alternative implementation using exec.LookPath for better command resolution
 func isWindows() bool {
 	return runtime.GOOS == "windows"
@@ -494,8 +1531,8 @@ var softDeleteProjectCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		// Validate flags
 		if projectName == "" {
-			fmt.Println("Error: --project flag is required")
-			os.Exit(1)
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
 		}
 
 		// Confirm deletion unless --force is specified
@@ -512,18 +1549,47 @@ var softDeleteProjectCmd = &cobra.Command{
 		// Initialize handler
 		handler, err := initHandler()
 		if err != nil {
-			fmt.Printf("Error initializing: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
 		}
 
 		// Delete project
 		err = handler.SoftDeleteProject(projectName)
 		if err != nil {
-			fmt.Printf("Error deleting project: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error deleting project: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		printSuccess("Successfully deleted project '%s'\n", projectName)
+	},
+}
+
+// Update project command
+var updateProjectCmd = &cobra.Command{
+	Use:   "update-project",
+	Short: "Update the description of a project",
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
 		}
 
-		fmt.Printf("Successfully deleted project '%s'\n", projectName)
+		// Update project
+		err = handler.UpdateProjectDescription(projectName, description)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error updating project: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		printSuccess("Successfully updated description of project '%s'\n", projectName)
 	},
 }
 
@@ -531,6 +1597,11 @@ var softDeleteProjectCmd = &cobra.Command{
 var environmentCmd = &cobra.Command{
 	Use:   "env",
 	Short: "Manage environments",
+	Long: `Manage environments. Every project has its own environments, selected with --project.
+Environments without a project are shared templates: the first time a project writes
+to an environment it doesn't have, it gets its own copy of the shared environment of
+that name, inheriting from its own copy of the template's parent. Reading such an
+environment finds no variables and creates nothing.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Default behavior is to list environments
 		cmd.Help()
@@ -540,20 +1611,20 @@ var environmentCmd = &cobra.Command{
 // List environments command
 var listEnvironmentsCmd = &cobra.Command{
 	Use:   "list",
-	Short: "List all environments",
+	Short: "List the environments of a project, or the shared environments",
 	Run: func(cmd *cobra.Command, args []string) {
 		// Initialize handler
 		handler, err := initHandler()
 		if err != nil {
-			fmt.Printf("Error initializing: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
 		}
 
 		// Get environments
-		environments, err := handler.ListEnvironments()
+		environments, err := handler.ListEnvironments(projectName)
 		if err != nil {
-			fmt.Printf("Error listing environments: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error listing environments: %v\n", err)
+			os.Exit(exitCode(err))
 		}
 
 		// Display environments
@@ -574,94 +1645,166 @@ var listEnvironmentsCmd = &cobra.Command{
 var createEnvironmentCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new environment",
+	Long: `Create a new environment in a project, or a shared environment without --project.
+Environment names are unique within a project regardless of case and are matched
+case-insensitively, so --env PROD finds an environment created as prod.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Validate flags
 		if environmentName == "" {
-			fmt.Println("Error: --name flag is required")
-			os.Exit(1)
+			fmt.Fprintln(os.Stderr, "Error: --name flag is required")
+			os.Exit(ExitValidation)
 		}
 
 		// Initialize handler
 		handler, err := initHandler()
 		if err != nil {
-			fmt.Printf("Error initializing: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
 		}
 
 		// Create environment
-		err = handler.CreateEnvironment(environmentName, description)
+		err = handler.CreateEnvironment(projectName, environmentName, description)
 		if err != nil {
-			fmt.Printf("Error creating environment: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error creating environment: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Set the parent environment
+		if parentName != "" {
+			err = handler.SetEnvironmentParent(projectName, environmentName, parentName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting parent environment: %v\n", err)
+				os.Exit(exitCode(err))
+			}
 		}
 
-		fmt.Printf("Successfully created environment '%s'\n", environmentName)
+		printSuccess("Successfully created environment '%s'\n", environmentName)
 	},
 }
 
-// Show project details command
-var projectDetailsCmd = &cobra.Command{
-	Use:   "project-details",
-	Short: "Show details of a project including its environments",
+// Update environment command
+var updateEnvironmentCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update the description or parent of an environment",
+	Long: `Update the description or parent of an environment of a project, or of a shared
+environment without --project.
+An environment inherits the variables of its parent when listed with --inherited.
+Pass an empty --parent to remove the parent.
+
+Examples:
+  go-env-cli env update --project myapp --name staging --description "Pre-production staging"
+  go-env-cli env update --project myapp --name prod --parent base`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Validate flags
-		if projectName == "" {
-			fmt.Println("Error: --project flag is required")
-			os.Exit(1)
+		if environmentName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --name flag is required")
+			os.Exit(ExitValidation)
+		}
+		if !cmd.Flags().Changed("description") && !cmd.Flags().Changed("parent") {
+			fmt.Fprintln(os.Stderr, "Error: --description or --parent flag is required")
+			os.Exit(ExitValidation)
 		}
 
 		// Initialize handler
 		handler, err := initHandler()
 		if err != nil {
-			fmt.Printf("Error initializing: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
 		}
 
-		// Get projects to find the specific one
-		projects, err := handler.ListProjects()
-		if err != nil {
-			fmt.Printf("Error listing projects: %v\n", err)
-			os.Exit(1)
+		// Update description
+		if cmd.Flags().Changed("description") {
+			err = handler.UpdateEnvironmentDescription(projectName, environmentName, description)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error updating environment: %v\n", err)
+				os.Exit(exitCode(err))
+			}
 		}
 
-		// Find the requested project
-		var foundProject models.Project
-		projectFound := false
-		for _, p := range projects {
-			if p.Name == projectName {
-				foundProject = p
-				projectFound = true
-				break
+		// Update parent
+		if cmd.Flags().Changed("parent") {
+			err = handler.SetEnvironmentParent(projectName, environmentName, parentName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error updating environment: %v\n", err)
+				os.Exit(exitCode(err))
 			}
 		}
 
-		if !projectFound {
-			fmt.Printf("Error: project '%s' not found\n", projectName)
-			os.Exit(1)
+		printSuccess("Successfully updated environment '%s'\n", environmentName)
+	},
+}
+
+// Show project details command
+var projectDetailsCmd = &cobra.Command{
+	Use:   "project-details",
+	Short: "Show details of a project including its environments and variable counts",
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Find the requested project, suggesting a close name when it doesn't exist
+		foundProject, err := handler.GetProject(projectName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting project '%s': %v\n", projectName, err)
+			os.Exit(exitCode(err))
 		}
 
 		// Get environments for the project
 		environments, err := handler.GetEnvironmentsForProject(projectName)
 		if err != nil {
-			fmt.Printf("Error getting environments for project: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error getting environments for project: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Get tags of the project
+		tags, err := handler.GetProjectTags(projectName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting project tags: %v\n", err)
+			os.Exit(exitCode(err))
 		}
 
 		// Display project details
-		fmt.Printf("Project: %s\n", foundProject.Name)
+		fmt.Printf("Project: %s\n", colors.Heading(foundProject.Name))
 		fmt.Printf("Description: %s\n", foundProject.Description)
-		fmt.Printf("Created: %s\n", foundProject.CreatedAt.Format("2006-01-02 15:04:05"))
+		fmt.Printf("Created: %s\n", foundProject.CreatedAt.Format(timestampFormat))
+		if len(tags) > 0 {
+			fmt.Printf("Tags: %s\n", strings.Join(tags, ", "))
+		}
 
 		if len(environments) == 0 {
 			fmt.Println("\nNo environments found for this project")
 			return
 		}
 
+		// Count the variables of each environment
+		counts, err := handler.GetVariableCountsByEnvironment(projectName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error counting environment variables: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+		variableCounts := make(map[string]int, len(counts))
+		total := 0
+		for _, c := range counts {
+			variableCounts[c.Name] = c.Count
+			total += c.Count
+		}
+
 		fmt.Println("\nEnvironments:")
 		fmt.Println("=============")
 		for _, e := range environments {
-			fmt.Printf("- %s: %s\n", e.Name, e.Description)
+			fmt.Printf("- %s (%d variables): %s\n", colors.Key(e.Name), variableCounts[e.Name], e.Description)
 		}
+		fmt.Printf("\nTotal: %d variables\n", total)
 	},
 }
 
@@ -669,53 +1812,167 @@ func init() {
 	// Import command flags
 	importCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
 	importCmd.Flags().StringVar(&environmentName, "env", "development", "Environment name (default: development)")
+	importCmd.Flags().StringVar(&addPrefix, "add-prefix", "", "Prefix added to every imported key (e.g. APP_)")
+	importCmd.Flags().BoolVar(&upperKeys, "upper", false, "Uppercase every key, failing if two keys differ only in case")
+	importCmd.Flags().StringVar(&importFormat, "format", handlers.FormatDotenv, "Input format ("+strings.Join(handlers.ImportFormats, ", ")+")")
+	importCmd.Flags().BoolVar(&noWarnSecrets, "no-warn-secrets", false, "Don't warn about values that look like real credentials")
+	importCmd.Flags().BoolVarP(&force, "force", "f", false, "Overwrite existing values without confirmation")
+	importCmd.Flags().BoolVar(&noTrim, "no-trim", false, "Keep the whitespace around unquoted values exactly as written")
+	importCmd.Flags().BoolVar(&strictDups, "strict-duplicates", false, "Fail when the file sets a key more than once instead of keeping the last value")
+	importCmd.Flags().StringArrayVar(&fetchHeaders, "header", nil, "HTTP header sent when importing from a URL (e.g. \"Authorization: Bearer $TOKEN\"), repeat for several")
+	importCmd.Flags().DurationVar(&fetchTimeout, "timeout", 30*time.Second, "Time allowed to download a file imported from a URL")
+	importCmd.Flags().Int64Var(&fetchMaxSize, "max-size", 10<<20, "Largest file in bytes imported from a URL")
+	importCmd.Flags().IntVar(&importWorkers, "parallel", 1, "Store the variables in N batches concurrently, each in its own transaction")
+	importCmd.Flags().BoolVar(&decrypt, "decrypt", false, "Decrypt a file encrypted with age or gpg before importing it")
+	importCmd.Flags().StringVar(&identityFile, "identity", "", "age identity file used by --decrypt (e.g. ~/.config/age/keys.txt)")
 	importCmd.MarkFlagRequired("project")
 
 	// Export command flags
 	exportCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
-	exportCmd.Flags().StringVar(&environmentName, "env", "development", "Environment name (default: development)")
+	exportCmd.Flags().StringArrayVar(&exportEnvs, "env", []string{"development"}, "Environment name, repeat to merge several with later ones winning")
 	exportCmd.Flags().BoolVarP(&force, "force", "f", false, "Force overwriting the file if it exists")
+	exportCmd.Flags().BoolVar(&withExport, "with-export", false, "Prefix each line with 'export ' so the file can be sourced by a shell")
+	exportCmd.Flags().StringVar(&exportFormat, "format", handlers.FormatDotenv, "Output format ("+strings.Join(handlers.ExportFormats, ", ")+")")
+	exportCmd.Flags().StringVar(&secretName, "name", "", "Secret name for --format k8s-secret (default: <project>-<env>)")
+	exportCmd.Flags().StringVar(&namespace, "namespace", "", "Secret namespace for --format k8s-secret")
+	exportCmd.Flags().BoolVar(&example, "example", false, "Write every key with a blank value, e.g. for a .env.example template")
+	exportCmd.Flags().StringVar(&placeholder, "placeholder", "", "Value written for every key with --example (e.g. \"<changeme>\")")
+	exportCmd.Flags().StringVar(&keyPrefix, "prefix", "", "Only export keys starting with this prefix (e.g. APP_)")
+	exportCmd.Flags().BoolVar(&stripPrefix, "strip-prefix", false, "Remove the --prefix from exported keys")
+	exportCmd.Flags().StringVar(&ssmPath, "path-prefix", "", "Parameter hierarchy for --format ssm (default: /<project>/<env>)")
+	exportCmd.Flags().BoolVar(&noValues, "no-values", false, "Leave the values out of a --format markdown table")
+	exportCmd.Flags().StringArrayVar(&exportKeys, "key", nil, "Only export this key, repeat to export several")
+	exportCmd.Flags().StringSliceVar(&onlyKeys, "only", nil, "Only export these comma-separated keys")
+	exportCmd.Flags().BoolVar(&ignoreMissing, "ignore-missing", false, "Skip --key and --only keys that don't exist instead of failing")
+	exportCmd.Flags().StringArrayVar(&excludeKeys, "exclude", nil, "Leave out keys matching this glob pattern (e.g. \"INTERNAL_*\"), repeat for several")
+	exportCmd.Flags().BoolVar(&writeChecksum, "checksum", false, "Also write the SHA-256 checksum of the file to FILE.sha256, checked by verify")
+	exportCmd.Flags().StringVar(&exportOrder, "order", handlers.OrderKey, "Order of the variables ("+strings.Join(handlers.ExportOrders, ", ")+")")
+	exportCmd.Flags().StringVar(&encryptTo, "encrypt-to", "", "Encrypt the file to this recipient with age (age1... or ssh- key) or gpg (email or key ID)")
 	exportCmd.MarkFlagRequired("project")
 
+	// Search env variable command flags
+	searchVarCmd.Flags().BoolVar(&showValues, "values", false, "Also print the value of each match")
+
 	// Set env command flags
 	setEnvCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
 	setEnvCmd.Flags().StringVar(&environmentName, "env", "development", "Environment name (default: development)")
 	setEnvCmd.Flags().StringVar(&keyName, "key", "", "Environment variable key (required)")
 	setEnvCmd.Flags().StringVar(&keyValue, "value", "", "Environment variable value")
+	setEnvCmd.Flags().StringVar(&valueFile, "value-file", "", "Read the value from a file (\"-\" for standard input)")
+	setEnvCmd.Flags().BoolVar(&base64Encode, "base64", false, "Store the value base64-encoded")
+	setEnvCmd.Flags().BoolVar(&ignoreCase, "ignore-case", false, "Update an existing variable whose key differs only in case")
+	setEnvCmd.Flags().BoolVar(&autoCreateEnv, "auto-create-env", false, "Create the environment if it doesn't exist yet")
+	setEnvCmd.Flags().BoolVar(&noWarnSecrets, "no-warn-secrets", false, "Don't warn when the value looks like a real credential")
 	setEnvCmd.MarkFlagRequired("project")
 	setEnvCmd.MarkFlagRequired("key")
+	setEnvCmd.MarkFlagsMutuallyExclusive("value", "value-file")
 
 	// Get env command flags
 	getEnvCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
 	getEnvCmd.Flags().StringVar(&environmentName, "env", "development", "Environment name (default: development)")
-	getEnvCmd.Flags().StringVar(&keyName, "key", "", "Environment variable key (required)")
+	getEnvCmd.Flags().StringArrayVar(&getKeys, "key", nil, "Environment variable key, repeat to get several (required)")
+	getEnvCmd.Flags().BoolVar(&valuesOnly, "values-only", false, "With several keys, print only the values in order")
+	getEnvCmd.Flags().BoolVar(&base64Decode, "base64-decode", false, "Decode a base64-encoded value and write the raw bytes")
+	getEnvCmd.Flags().BoolVar(&strictKeys, "strict", false, "With several keys, fail without printing anything if any key is missing")
+	getEnvCmd.Flags().StringVar(&defaultValue, "default", "", "Value to print when the variable doesn't exist")
+	getEnvCmd.Flags().BoolVar(&ignoreCase, "ignore-case", false, "Match keys regardless of case")
+	getEnvCmd.Flags().BoolVar(&clip, "clip", false, "Copy the value to the clipboard instead of printing it")
 	getEnvCmd.MarkFlagRequired("project")
 	getEnvCmd.MarkFlagRequired("key")
 
+	// Has env command flags
+	hasEnvCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	hasEnvCmd.Flags().StringVar(&environmentName, "env", "development", "Environment name (default: development)")
+	hasEnvCmd.Flags().StringVar(&keyName, "key", "", "Environment variable key (required)")
+	hasEnvCmd.MarkFlagRequired("project")
+	hasEnvCmd.MarkFlagRequired("key")
+
 	// Delete env command flags
 	deleteEnvCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
 	deleteEnvCmd.Flags().StringVar(&environmentName, "env", "development", "Environment name (default: development)")
-	deleteEnvCmd.Flags().StringVar(&keyName, "key", "", "Environment variable key (required)")
+	deleteEnvCmd.Flags().StringVar(&keyName, "key", "", "Environment variable key")
+	deleteEnvCmd.Flags().StringVar(&keyPattern, "pattern", "", "Delete all variables whose key matches this pattern (e.g. \"OLD_*\")")
+	deleteEnvCmd.Flags().BoolVarP(&force, "force", "f", false, "Skip confirmation")
+	deleteEnvCmd.Flags().BoolVar(&ignoreCase, "ignore-case", false, "Match --key regardless of case")
 	deleteEnvCmd.MarkFlagRequired("project")
-	deleteEnvCmd.MarkFlagRequired("key")
+
+	// Rename env command flags
+	renameEnvCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	renameEnvCmd.Flags().StringVar(&environmentName, "env", "development", "Environment name (default: development)")
+	renameEnvCmd.Flags().StringVar(&keyName, "key", "", "Current environment variable key (required)")
+	renameEnvCmd.Flags().StringVar(&newKeyName, "new-key", "", "New environment variable key (required)")
+	renameEnvCmd.Flags().BoolVar(&overwrite, "overwrite", false, "Replace the variable if the new key already exists")
+	renameEnvCmd.MarkFlagRequired("project")
+	renameEnvCmd.MarkFlagRequired("key")
+	renameEnvCmd.MarkFlagRequired("new-key")
+
+	// Move env command flags
+	moveEnvCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	moveEnvCmd.Flags().StringVar(&fromEnvironmentName, "from", "", "Source environment name (required)")
+	moveEnvCmd.Flags().StringVar(&toEnvironmentName, "to", "", "Destination environment name (required)")
+	moveEnvCmd.Flags().StringVar(&keyName, "key", "", "Environment variable key (required)")
+	moveEnvCmd.Flags().BoolVar(&overwrite, "overwrite", false, "Replace the variable if the key already exists in the destination")
+	moveEnvCmd.MarkFlagRequired("project")
+	moveEnvCmd.MarkFlagRequired("from")
+	moveEnvCmd.MarkFlagRequired("to")
+	moveEnvCmd.MarkFlagRequired("key")
 
 	// List env command flags
 	listEnvCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
 	listEnvCmd.Flags().StringVar(&environmentName, "env", "development", "Environment name (default: development)")
-	listEnvCmd.Flags().StringVar(&runCommand, "run", "", "Command to run with environment variables loaded")
+	listEnvCmd.Flags().BoolVar(&watch, "watch", false, "Restart the command with the new variables whenever they change")
+	listEnvCmd.Flags().DurationVar(&watchInterval, "watch-interval", 2*time.Second, "How often --watch checks the variables for changes")
+	listEnvCmd.Flags().StringVar(&runCommand, "run", "", "Command to run through the shell with environment variables loaded (prefer passing the command after --)")
 	listEnvCmd.Flags().StringVar(&keyName, "filter", "", "Filter by key pattern")
+	listEnvCmd.Flags().StringVar(&keyRegex, "regex", "", "Filter by a regular expression matched against keys")
+	listEnvCmd.Flags().BoolVar(&cleanEnv, "clean", false, "Run the command with only the project's variables instead of the current environment")
+	listEnvCmd.Flags().StringSliceVar(&keepEnv, "keep", nil, "Current environment variables to keep with --clean (e.g. PATH,HOME)")
+	listEnvCmd.Flags().BoolVar(&tableOutput, "table", false, "Print variables as an aligned table")
+	listEnvCmd.Flags().BoolVar(&inherited, "inherited", false, "Include variables inherited from parent environments")
+	listEnvCmd.Flags().BoolVarP(&longOutput, "long", "l", false, "Also show when each variable was last updated")
+	listEnvCmd.Flags().BoolVar(&listDeleted, "deleted", false, "List soft-deleted variables with their deletion time")
+	listEnvCmd.Flags().IntVar(&pageLimit, "limit", 0, "Maximum number of variables to list (0 for all)")
+	listEnvCmd.Flags().IntVar(&pageOffset, "offset", 0, "Number of variables to skip, in key order")
+	listEnvCmd.Flags().StringVar(&updatedWithin, "since", "", "Only list variables updated within this window (e.g. 7d, 24h)")
+	listEnvCmd.Flags().StringVar(&createdWithin, "created-since", "", "Only list variables created within this window (e.g. 7d, 24h)")
 	listEnvCmd.MarkFlagRequired("project")
 
+	// List projects command flags
+	listProjectsCmd.Flags().BoolVar(&tableOutput, "table", false, "Print projects as an aligned table")
+	listProjectsCmd.Flags().StringVar(&projectFilter, "filter", "", "Only list projects whose name contains this pattern")
+	listProjectsCmd.Flags().StringVar(&projectSort, "sort", "name", "Sort by name, created or updated (newest first)")
+	listProjectsCmd.Flags().IntVar(&projectLimit, "limit", 0, "Maximum number of projects to list (0 for all)")
+	listProjectsCmd.Flags().StringArrayVar(&projectTags, "tag", nil, "Only list projects with this tag, repeat to require several")
+	listProjectsCmd.RegisterFlagCompletionFunc("filter", completeProjectNames)
+
 	// Delete project command flags
 	softDeleteProjectCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
 	softDeleteProjectCmd.Flags().BoolVarP(&force, "force", "f", false, "Skip confirmation")
 	softDeleteProjectCmd.MarkFlagRequired("project")
 
+	// Update project command flags
+	updateProjectCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	updateProjectCmd.Flags().StringVar(&description, "description", "", "Project description (required)")
+	updateProjectCmd.MarkFlagRequired("project")
+	updateProjectCmd.MarkFlagRequired("description")
+
+	// List environments command flags
+	listEnvironmentsCmd.Flags().StringVar(&projectName, "project", "", "Project name (default: the shared environments)")
+
 	// Create environment command flags
+	createEnvironmentCmd.Flags().StringVar(&projectName, "project", "", "Project name (default: create a shared environment)")
 	createEnvironmentCmd.Flags().StringVar(&environmentName, "name", "", "Environment name (required)")
 	createEnvironmentCmd.Flags().StringVar(&description, "description", "", "Environment description")
+	createEnvironmentCmd.Flags().StringVar(&parentName, "parent", "", "Environment to inherit variables from")
 	createEnvironmentCmd.MarkFlagRequired("name")
 
+	// Update environment command flags
+	updateEnvironmentCmd.Flags().StringVar(&projectName, "project", "", "Project name (default: update a shared environment)")
+	updateEnvironmentCmd.Flags().StringVar(&environmentName, "name", "", "Environment name (required)")
+	updateEnvironmentCmd.Flags().StringVar(&description, "description", "", "Environment description")
+	updateEnvironmentCmd.Flags().StringVar(&parentName, "parent", "", "Environment to inherit variables from (empty to remove)")
+	updateEnvironmentCmd.MarkFlagRequired("name")
+
 	// Project details command flags
 	projectDetailsCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
 	projectDetailsCmd.MarkFlagRequired("project")
@@ -723,4 +1980,5 @@ func init() {
 	// Add environment subcommands
 	environmentCmd.AddCommand(listEnvironmentsCmd)
 	environmentCmd.AddCommand(createEnvironmentCmd)
+	environmentCmd.AddCommand(updateEnvironmentCmd)
 }
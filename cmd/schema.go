@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	valueTypeDeclaration string
+	requiredKey          bool
+)
+
+// schemaCmd represents the schema command
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Manage the declared value types of a project's variables",
+	Long: `Manage the declared value types of a project's variables. Once a type is declared,
+set, import and edit reject values that don't match it. Variables can also be marked
+as required, which the validate command checks.
+
+Types: string, int, bool, url, enum(a,b,...)`,
+}
+
+// schemaSetCmd represents the schema set command
+var schemaSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Declare the value type of a variable or mark it as required",
+	Long: `Declare the value type of a variable, replacing any previous declaration, and/or
+mark it as required in every environment with --required (--required=false to undo).
+Required variables are checked by the validate command.
+
+Examples:
+  go-env-cli schema set --project test --key PORT --type int
+  go-env-cli schema set --project test --key LOG_LEVEL --type "enum(debug,info,warn,error)"
+  go-env-cli schema set --project test --key DATABASE_URL --type url --required`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" || keyName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project and --key flags are required")
+			os.Exit(ExitValidation)
+		}
+		if valueTypeDeclaration == "" && !cmd.Flags().Changed("required") {
+			fmt.Fprintln(os.Stderr, "Error: --type or --required is required")
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Declare type
+		if valueTypeDeclaration != "" {
+			err = handler.SetVariableType(projectName, keyName, valueTypeDeclaration)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting variable type: %v\n", err)
+				os.Exit(exitCode(err))
+			}
+			printSuccess("Successfully declared %s as %s in project '%s'\n", keyName, valueTypeDeclaration, projectName)
+		}
+
+		// Mark as required or optional
+		if cmd.Flags().Changed("required") {
+			err = handler.SetVariableRequired(projectName, keyName, requiredKey)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error setting variable required: %v\n", err)
+				os.Exit(exitCode(err))
+			}
+			if requiredKey {
+				printSuccess("Successfully marked %s as required in project '%s'\n", keyName, projectName)
+			} else {
+				printSuccess("Successfully marked %s as optional in project '%s'\n", keyName, projectName)
+			}
+		}
+	},
+}
+
+// schemaListCmd represents the schema list command
+var schemaListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the declared value types of a project's variables",
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Get types
+		variableTypes, err := handler.ListVariableTypes(projectName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing variable types: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Display types
+		if len(variableTypes) == 0 {
+			fmt.Printf("No variable types declared for project '%s'\n", projectName)
+			return
+		}
+
+		fmt.Printf("Variable types for project '%s':\n", projectName)
+		fmt.Println("=================================================")
+		for _, t := range variableTypes {
+			if t.Required {
+				fmt.Printf("- %s: %s (required)\n", t.Key, t.Type)
+			} else {
+				fmt.Printf("- %s: %s\n", t.Key, t.Type)
+			}
+		}
+	},
+}
+
+// schemaDeleteCmd represents the schema delete command
+var schemaDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Remove the declared value type and required flag of a variable",
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" || keyName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project and --key flags are required")
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Remove type
+		err = handler.DeleteVariableType(projectName, keyName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error deleting variable type: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		printSuccess("Successfully removed the type of %s in project '%s'\n", keyName, projectName)
+	},
+}
+
+func init() {
+	schemaSetCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	schemaSetCmd.Flags().StringVar(&keyName, "key", "", "Environment variable key (required)")
+	schemaSetCmd.Flags().StringVar(&valueTypeDeclaration, "type", "", "Value type: string, int, bool, url or enum(a,b,...)")
+	schemaSetCmd.Flags().BoolVar(&requiredKey, "required", false, "Require the variable to be set in every environment")
+	schemaSetCmd.MarkFlagRequired("project")
+	schemaSetCmd.MarkFlagRequired("key")
+
+	schemaListCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	schemaListCmd.MarkFlagRequired("project")
+
+	schemaDeleteCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	schemaDeleteCmd.Flags().StringVar(&keyName, "key", "", "Environment variable key (required)")
+	schemaDeleteCmd.MarkFlagRequired("project")
+	schemaDeleteCmd.MarkFlagRequired("key")
+
+	schemaCmd.AddCommand(schemaSetCmd)
+	schemaCmd.AddCommand(schemaListCmd)
+	schemaCmd.AddCommand(schemaDeleteCmd)
+	rootCmd.AddCommand(schemaCmd)
+}
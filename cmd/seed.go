@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"go-env-cli/internal/app/handlers"
+
+	"github.com/spf13/cobra"
+)
+
+// seedCmd represents the seed command
+var seedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "Create the default environments if they don't exist",
+	Long: `Create the default development, staging and production environments if they don't
+exist yet, so commands defaulting to --env development work on a new installation.
+Environments are shared by every project unless --project is given, in which case
+the project gets its own. Running seed again changes nothing.
+
+Examples:
+  go-env-cli seed
+  go-env-cli seed --project my-project`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		created, err := handler.SeedEnvironments(projectName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error seeding environments: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		if len(created) == 0 {
+			printSuccess("All %d default environments already exist\n", len(handlers.DefaultEnvironments))
+			return
+		}
+
+		printSuccess("Created %d environment(s):\n", len(created))
+		for _, name := range created {
+			printSuccess("- %s\n", name)
+		}
+	},
+}
+
+func init() {
+	seedCmd.Flags().StringVar(&projectName, "project", "", "Create the environments in this project instead of as shared environments")
+	rootCmd.AddCommand(seedCmd)
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-env-cli/internal/app/handlers"
+
+	"github.com/spf13/cobra"
+)
+
+var shellName string
+
+// shellFormats maps the shells supported by shellenv to their export format
+var shellFormats = map[string]string{
+	"bash":       handlers.FormatEnvrc,
+	"zsh":        handlers.FormatEnvrc,
+	"sh":         handlers.FormatEnvrc,
+	"fish":       handlers.FormatFish,
+	"powershell": handlers.FormatPowerShell,
+}
+
+// shellenvCmd represents the shellenv command
+var shellenvCmd = &cobra.Command{
+	Use:   "shellenv",
+	Short: "Print commands that load a project's variables into the current shell",
+	Long: `Print commands that load a project's variables into the current shell, to be
+evaluated by the shell. Output always goes to standard output and nothing is prompted.
+The shell is detected from $SHELL unless --shell is given (bash, zsh, sh, fish, powershell).
+
+Examples:
+  eval "$(go-env-cli shellenv --project test --env local)"
+  go-env-cli shellenv --project test --env local --shell fish | source
+  go-env-cli shellenv --project test --env local --shell powershell | Out-String | Invoke-Expression`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
+		}
+		if environmentName == "" {
+			environmentName = "development" // Default to development
+		}
+
+		shell := shellName
+		if shell == "" {
+			shell = detectShell()
+		}
+
+		format, ok := shellFormats[shell]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: unsupported shell '%s' (supported: bash, zsh, sh, fish, powershell)\n", shell)
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Print the commands
+		err = handler.ExportEnv(os.Stdout, projectName, environmentName, handlers.ExportOptions{Format: format})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting environment variables: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+	},
+}
+
+// detectShell guesses the user's shell from $SHELL, defaulting to PowerShell on
+// Windows and bash elsewhere
+func detectShell() string {
+	if shell := os.Getenv("SHELL"); shell != "" {
+		name := strings.TrimSuffix(filepath.Base(shell), ".exe")
+		if name == "pwsh" {
+			return "powershell"
+		}
+		if _, ok := shellFormats[name]; ok {
+			return name
+		}
+	}
+
+	if isWindows() {
+		return "powershell"
+	}
+	return "bash"
+}
+
+func init() {
+	shellenvCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	shellenvCmd.Flags().StringVar(&environmentName, "env", "development", "Environment name (default: development)")
+	shellenvCmd.Flags().StringVar(&shellName, "shell", "", "Shell to print commands for (default: detected from $SHELL)")
+	shellenvCmd.MarkFlagRequired("project")
+	rootCmd.AddCommand(shellenvCmd)
+}
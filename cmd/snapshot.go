@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var snapshotID int64
+
+// snapshotCmd represents the snapshot command
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Record and roll back to point-in-time copies of an environment",
+	Long: `Record point-in-time copies of every variable of a project environment and roll the
+whole environment back to one of them.`,
+}
+
+// snapshotCreateCmd represents the snapshot create command
+var snapshotCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Record the current variables of a project environment",
+	Long: `Record the current key/value set of a project environment in a new snapshot and print
+its ID, which "snapshot restore --id" takes.
+
+Examples:
+  go-env-cli snapshot create --project test --env prod
+  go-env-cli snapshot create --project test --env prod --description "before the 2.0 rollout"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" || environmentName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project and --env flags are required")
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Create snapshot
+		snapshot, err := handler.CreateSnapshot(projectName, environmentName, description)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating snapshot: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		printSuccess("Successfully created snapshot %d of project '%s' (%s environment) with %d variables\n",
+			snapshot.ID, projectName, environmentName, snapshot.Variables)
+	},
+}
+
+// snapshotListCmd represents the snapshot list command
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the snapshots of a project environment",
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" || environmentName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project and --env flags are required")
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Get snapshots
+		snapshots, err := handler.ListSnapshots(projectName, environmentName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing snapshots: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Display snapshots
+		if len(snapshots) == 0 {
+			fmt.Printf("No snapshots found for project '%s' (%s environment)\n", projectName, environmentName)
+			return
+		}
+
+		fmt.Printf("Snapshots for project '%s' (%s environment):\n", projectName, environmentName)
+		fmt.Println("=================================================")
+		for _, s := range snapshots {
+			line := fmt.Sprintf("- %d: %s, %d variables", s.ID, s.CreatedAt.Format("2006-01-02 15:04:05"), s.Variables)
+			if s.Description != "" {
+				line += " (" + s.Description + ")"
+			}
+			fmt.Println(line)
+		}
+	},
+}
+
+// snapshotRestoreCmd represents the snapshot restore command
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Roll a project environment back to a snapshot",
+	Long: `Make the variables of a snapshot's project environment match the snapshot exactly, in a
+single transaction: variables that changed or were deleted since are set back, and
+variables added since are deleted. You are asked to confirm unless --force is given.
+
+Examples:
+  go-env-cli snapshot restore --id 12
+  go-env-cli snapshot restore --id 12 --force`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if snapshotID <= 0 {
+			fmt.Fprintln(os.Stderr, "Error: --id flag is required")
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		snapshot, err := handler.GetSnapshot(snapshotID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting snapshot: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Confirm restore unless --force is specified
+		if !force {
+			fmt.Printf("Are you sure you want to restore snapshot %d from %s (%d variables)? Changes made since will be lost. [y/N]: ",
+				snapshot.ID, snapshot.CreatedAt.Format("2006-01-02 15:04:05"), snapshot.Variables)
+			var response string
+			fmt.Scanln(&response)
+			if response != "y" && response != "Y" {
+				fmt.Println("Restore cancelled")
+				return
+			}
+		}
+
+		// Restore snapshot
+		restore, err := handler.RestoreSnapshot(snapshotID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error restoring snapshot: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		if len(restore.Set) > 0 {
+			printSuccess("Set: %s\n", strings.Join(restore.Set, ", "))
+		}
+		if len(restore.Deleted) > 0 {
+			printSuccess("Deleted: %s\n", strings.Join(restore.Deleted, ", "))
+		}
+		printSuccess("Successfully restored snapshot %d (%d set, %d deleted)\n",
+			snapshot.ID, len(restore.Set), len(restore.Deleted))
+	},
+}
+
+func init() {
+	snapshotCreateCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	snapshotCreateCmd.Flags().StringVar(&environmentName, "env", "", "Environment name (required)")
+	snapshotCreateCmd.Flags().StringVar(&description, "description", "", "Note describing the snapshot")
+	snapshotCreateCmd.MarkFlagRequired("project")
+	snapshotCreateCmd.MarkFlagRequired("env")
+
+	snapshotListCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	snapshotListCmd.Flags().StringVar(&environmentName, "env", "", "Environment name (required)")
+	snapshotListCmd.MarkFlagRequired("project")
+	snapshotListCmd.MarkFlagRequired("env")
+
+	snapshotRestoreCmd.Flags().Int64Var(&snapshotID, "id", 0, "Snapshot ID (required)")
+	snapshotRestoreCmd.Flags().BoolVarP(&force, "force", "f", false, "Restore without asking for confirmation")
+	snapshotRestoreCmd.MarkFlagRequired("id")
+
+	snapshotCmd.AddCommand(snapshotCreateCmd)
+	snapshotCmd.AddCommand(snapshotListCmd)
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
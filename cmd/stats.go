@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show counts of projects, environments and variables",
+	Long: `Show counts of active projects, environments and variables, with a per-project
+breakdown of variable counts. Use --project to show a per-environment breakdown for a
+single project instead.
+
+Examples:
+  go-env-cli stats
+  go-env-cli stats --project my-project`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Per-environment breakdown for a single project
+		if projectName != "" {
+			counts, err := handler.GetVariableCountsByEnvironment(projectName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error getting statistics: %v\n", err)
+				os.Exit(exitCode(err))
+			}
+
+			total := 0
+			fmt.Printf("Variables per environment for project '%s':\n", projectName)
+			fmt.Println("============================================")
+			for _, c := range counts {
+				fmt.Printf("- %s: %d\n", c.Name, c.Count)
+				total += c.Count
+			}
+			fmt.Printf("\nTotal: %d\n", total)
+			return
+		}
+
+		// Global statistics
+		stats, err := handler.Stats()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting statistics: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		fmt.Printf("Projects:     %d\n", stats.Projects)
+		fmt.Printf("Environments: %d\n", stats.Environments)
+		fmt.Printf("Variables:    %d\n", stats.Variables)
+
+		if len(stats.PerProject) == 0 {
+			return
+		}
+
+		fmt.Println("\nVariables per project:")
+		fmt.Println("======================")
+		for _, c := range stats.PerProject {
+			fmt.Printf("- %s: %d\n", c.Name, c.Count)
+		}
+	},
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&projectName, "project", "", "Show a per-environment breakdown for this project")
+	rootCmd.AddCommand(statsCmd)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"go-env-cli/internal/app/models"
+
+	"github.com/google/uuid"
+)
+
+// timestampFormat is the layout used to print creation and update times
+const timestampFormat = "2006-01-02 15:04:05"
+
+// newTableWriter creates a tabwriter that aligns columns separated by tabs
+func newTableWriter(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+}
+
+// renderVariablesTable writes environment variables as an aligned KEY/VALUE table
+func renderVariablesTable(w io.Writer, variables []models.EnvVariable) error {
+	tw := newTableWriter(w)
+	fmt.Fprintln(tw, "KEY\tVALUE")
+	for _, v := range variables {
+		fmt.Fprintf(tw, "%s\t%s\n", v.Key, v.Value)
+	}
+	return tw.Flush()
+}
+
+// renderVariablesLongTable writes environment variables as an aligned table that also
+// shows when each variable was last updated. When sources is not nil a SOURCE column
+// shows the name of the environment each variable comes from, looked up by environment ID.
+func renderVariablesLongTable(w io.Writer, variables []models.EnvVariable, sources map[uuid.UUID]string) error {
+	tw := newTableWriter(w)
+	if sources != nil {
+		fmt.Fprintln(tw, "KEY\tVALUE\tUPDATED\tSOURCE")
+	} else {
+		fmt.Fprintln(tw, "KEY\tVALUE\tUPDATED")
+	}
+	for _, v := range variables {
+		if sources != nil {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", v.Key, v.Value, v.UpdatedAt.Format(timestampFormat), sources[v.EnvironmentID])
+		} else {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", v.Key, v.Value, v.UpdatedAt.Format(timestampFormat))
+		}
+	}
+	return tw.Flush()
+}
+
+// renderDeletedVariablesTable writes soft-deleted environment variables as an aligned
+// table including their deletion time
+func renderDeletedVariablesTable(w io.Writer, variables []models.EnvVariable) error {
+	tw := newTableWriter(w)
+	fmt.Fprintln(tw, "KEY\tVALUE\tDELETED")
+	for _, v := range variables {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", v.Key, v.Value, v.DeletedAt.Format(timestampFormat))
+	}
+	return tw.Flush()
+}
+
+// renderProjectsTable writes projects as an aligned table including the number of
+// environments each project uses, looked up by project ID in environmentCounts
+func renderProjectsTable(w io.Writer, projects []models.Project, environmentCounts map[uuid.UUID]int) error {
+	tw := newTableWriter(w)
+	fmt.Fprintln(tw, "PROJECT\tDESCRIPTION\tENVIRONMENTS\tCREATED")
+	for _, p := range projects {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n",
+			p.Name, p.Description, environmentCounts[p.ID], p.CreatedAt.Format(timestampFormat))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"go-env-cli/internal/app/models"
+
+	"github.com/google/uuid"
+)
+
+func TestRenderVariablesTable(t *testing.T) {
+	tests := []struct {
+		name      string
+		variables []models.EnvVariable
+		want      string
+	}{
+		{"empty", nil, "KEY  VALUE\n"},
+		{
+			"aligned",
+			[]models.EnvVariable{{Key: "A", Value: "1"}, {Key: "LONG_KEY", Value: "two words"}},
+			"KEY       VALUE\n" +
+				"A         1\n" +
+				"LONG_KEY  two words\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := renderVariablesTable(&buf, tt.variables); err != nil {
+				t.Fatalf("renderVariablesTable() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("renderVariablesTable() =\n%s\nwant\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderProjectsTable(t *testing.T) {
+	created := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	web := models.Project{ID: uuid.New(), Name: "web", Description: "Web site", CreatedAt: created}
+	api := models.Project{ID: uuid.New(), Name: "api-gateway", CreatedAt: created}
+
+	tests := []struct {
+		name     string
+		projects []models.Project
+		counts   map[uuid.UUID]int
+		want     string
+	}{
+		{"empty", nil, nil, "PROJECT  DESCRIPTION  ENVIRONMENTS  CREATED\n"},
+		{
+			"counts",
+			[]models.Project{web, api},
+			map[uuid.UUID]int{web.ID: 3},
+			"PROJECT      DESCRIPTION  ENVIRONMENTS  CREATED\n" +
+				"web          Web site     3             2024-03-01 09:30:00\n" +
+				"api-gateway               0             2024-03-01 09:30:00\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := renderProjectsTable(&buf, tt.projects, tt.counts); err != nil {
+				t.Fatalf("renderProjectsTable() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("renderProjectsTable() =\n%s\nwant\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
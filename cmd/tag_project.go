@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	addTags    []string
+	removeTags []string
+)
+
+// tagProjectCmd represents the tag-project command
+var tagProjectCmd = &cobra.Command{
+	Use:   "tag-project",
+	Short: "Add or remove tags of a project",
+	Long: `Add or remove tags of a project, such as team:payments or tier:critical, and print
+the tags it has afterwards. Without --add or --remove the current tags are printed.
+Use "list-projects --tag" to list the projects with a tag.
+
+Examples:
+  go-env-cli tag-project --project billing --add team:payments --add tier:critical
+  go-env-cli tag-project --project billing --remove tier:critical
+  go-env-cli tag-project --project billing`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Update tags
+		tags, err := handler.TagProject(projectName, addTags, removeTags)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error tagging project: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		if len(tags) == 0 {
+			fmt.Printf("Project '%s' has no tags\n", projectName)
+			return
+		}
+		fmt.Printf("Tags of project '%s': %s\n", projectName, strings.Join(tags, ", "))
+	},
+}
+
+func init() {
+	tagProjectCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	tagProjectCmd.Flags().StringArrayVar(&addTags, "add", nil, "Tag to add, repeat to add several")
+	tagProjectCmd.Flags().StringArrayVar(&removeTags, "remove", nil, "Tag to remove, repeat to remove several")
+	tagProjectCmd.MarkFlagRequired("project")
+	rootCmd.AddCommand(tagProjectCmd)
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"go-env-cli/internal/app/handlers"
+
+	"github.com/spf13/cobra"
+)
+
+// undoCmd represents the undo command
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Undo the most recent set, delete or rename in an environment",
+	Long: `Undo the most recent set, delete or rename of a variable in a project environment, as
+recorded in the variable history. The change is shown and you are asked to confirm
+unless --force is given. Running undo again steps further back.
+
+Changes made by import, edit, move, snapshot restore and delete --pattern are recorded
+one variable at a time, so undo reverses them one variable at a time. Restoring a
+backup and cloning a project are not recorded.
+
+Examples:
+  go-env-cli undo --project test --env prod
+  go-env-cli undo --project test --env prod --force`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" || environmentName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project and --env flags are required")
+			os.Exit(ExitValidation)
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		entry, err := handler.LastChange(projectName, environmentName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error finding the last change: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		// Confirm unless --force is specified
+		fmt.Printf("Last change: %s %s at %s\n", entry.Operation, entry.Key, entry.CreatedAt.Format("2006-01-02 15:04:05"))
+		fmt.Printf("Undo will %s\n", handlers.DescribeUndo(entry))
+		if !force {
+			fmt.Print("Undo this change? [y/N]: ")
+			var response string
+			fmt.Scanln(&response)
+			if response != "y" && response != "Y" {
+				fmt.Println("Undo cancelled")
+				return
+			}
+		}
+
+		// Undo
+		if err := handler.UndoChange(entry); err != nil {
+			fmt.Fprintf(os.Stderr, "Error undoing change: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		printSuccess("Successfully undid %s %s in project '%s' (%s environment)\n",
+			entry.Operation, entry.Key, projectName, environmentName)
+	},
+}
+
+func init() {
+	undoCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	undoCmd.Flags().StringVar(&environmentName, "env", "", "Environment name (required)")
+	undoCmd.Flags().BoolVarP(&force, "force", "f", false, "Undo without asking for confirmation")
+	undoCmd.MarkFlagRequired("project")
+	undoCmd.MarkFlagRequired("env")
+	rootCmd.AddCommand(undoCmd)
+}
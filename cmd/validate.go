@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var jsonOutput bool
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check that an environment sets every required variable with valid values",
+	Long: `Check that every variable marked as required with "schema set --required" is set to a
+non-empty value in an environment, and that all values match their declared types.
+Exits with status 1 when any check fails. Use --json for machine-readable output in CI.
+
+Examples:
+  go-env-cli validate --project test --env production
+  go-env-cli validate --project test --env production --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Validate flags
+		if projectName == "" {
+			fmt.Fprintln(os.Stderr, "Error: --project flag is required")
+			os.Exit(ExitValidation)
+		}
+		if environmentName == "" {
+			environmentName = "development" // Default to development
+		}
+
+		// Initialize handler
+		handler, err := initHandler()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		result, err := handler.ValidateEnvironment(projectName, environmentName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error validating environment: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+
+		if jsonOutput {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			encoder.Encode(result)
+		} else if result.Valid {
+			fmt.Printf("Project '%s' (%s environment) is valid\n", projectName, environmentName)
+		} else {
+			fmt.Printf("Project '%s' (%s environment) is invalid:\n", projectName, environmentName)
+			for _, key := range result.Missing {
+				fmt.Printf("- %s: required but not set\n", key)
+			}
+			for _, key := range result.Empty {
+				fmt.Printf("- %s: required but empty\n", key)
+			}
+			for _, issue := range result.Invalid {
+				fmt.Printf("- %s\n", issue.Message)
+			}
+		}
+
+		if !result.Valid {
+			os.Exit(ExitValidation)
+		}
+	},
+}
+
+func init() {
+	validateCmd.Flags().StringVar(&projectName, "project", "", "Project name (required)")
+	validateCmd.Flags().StringVar(&environmentName, "env", "development", "Environment name (default: development)")
+	validateCmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the result as JSON")
+	validateCmd.MarkFlagRequired("project")
+	rootCmd.AddCommand(validateCmd)
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"go-env-cli/internal/pkg/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify FILE",
+	Short: "Check a file against the checksum written by export --checksum",
+	Long: `Check that a file matches the SHA-256 checksum recorded next to it in FILE.sha256,
+as written by export --checksum, to detect files tampered with or truncated in
+transit. Exits with status 3 when the file doesn't match. The database isn't used.
+
+Examples:
+  go-env-cli export .env --project test --env prod --checksum
+  go-env-cli verify .env`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		filePath := args[0]
+
+		if err := utils.VerifyChecksumFile(filePath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error verifying %s: %v\n", filePath, err)
+			os.Exit(exitCode(err))
+		}
+
+		printSuccess("%s matches its checksum\n", filePath)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+}
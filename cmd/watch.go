@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"go-env-cli/internal/app/models"
+	"go-env-cli/internal/pkg/logger"
+)
+
+// stopTimeout is how long a watched command is given to exit after being asked to
+// terminate before it is killed
+const stopTimeout = 5 * time.Second
+
+// watchCommand runs the command built by newCmd with the variables returned by load,
+// and restarts it with the new variables whenever they change. The variables are
+// reloaded every interval, and a change is only acted on once they have stayed the
+// same for a whole interval, so a burst of edits restarts the command once. A command
+// that exits by itself is started again on the next change. Ctrl-C stops the command
+// and returns.
+func watchCommand(newCmd func() *exec.Cmd, load func() ([]models.EnvVariable, error), variables []models.EnvVariable, interval time.Duration) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	running := newCmd()
+	exited, err := startWatched(running, commandEnv(variables, cleanEnv, keepEnv))
+	if err != nil {
+		return err
+	}
+
+	current := variablesFingerprint(variables)
+	pending := ""
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-signals:
+			stopWatched(running, exited)
+			return nil
+
+		case err := <-exited:
+			if err != nil {
+				logger.Warnf("Command exited: %v, waiting for changes", err)
+			} else {
+				logger.Infof("Command exited, waiting for changes")
+			}
+			running, exited = nil, nil
+
+		case <-ticker.C:
+			reloaded, err := load()
+			if err != nil {
+				logger.Warnf("Failed to reload environment variables: %v", err)
+				continue
+			}
+
+			// Wait for the variables to settle before restarting
+			fingerprint := variablesFingerprint(reloaded)
+			if fingerprint == current {
+				pending = ""
+				continue
+			}
+			if fingerprint != pending {
+				pending = fingerprint
+				continue
+			}
+
+			logger.Infof("Environment variables changed, restarting command")
+			stopWatched(running, exited)
+			current, pending = fingerprint, ""
+
+			running = newCmd()
+			if exited, err = startWatched(running, commandEnv(reloaded, cleanEnv, keepEnv)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// startWatched starts cmd attached to the terminal with the provided environment, in a
+// process group of its own. The returned channel receives the result of the command
+// once it exits.
+func startWatched(cmd *exec.Cmd, env []string) (chan error, error) {
+	cmd.Env = env
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	startInGroup(cmd)
+
+	logger.Debugf("Running %s with %d environment variables", strings.Join(cmd.Args, " "), len(env))
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start command: %w", err)
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	return exited, nil
+}
+
+// stopWatched asks a command started by startWatched, and the processes it started, to
+// terminate, killing them if the command hasn't exited within stopTimeout. A nil cmd
+// has already exited.
+func stopWatched(cmd *exec.Cmd, exited chan error) {
+	if cmd == nil {
+		return
+	}
+
+	terminateGroup(cmd)
+	select {
+	case <-exited:
+	case <-time.After(stopTimeout):
+		killGroup(cmd)
+		<-exited
+	}
+}
+
+// variablesFingerprint returns a string that changes whenever a key or value of
+// variables does
+func variablesFingerprint(variables []models.EnvVariable) string {
+	pairs := make([]string, 0, len(variables))
+	for _, v := range variables {
+		pairs = append(pairs, fmt.Sprintf("%q=%q", v.Key, v.Value))
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, "\n")
+}
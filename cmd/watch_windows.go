@@ -0,0 +1,18 @@
+//go:build windows
+
+package cmd
+
+import "os/exec"
+
+// startInGroup is a no-op on Windows, where commands are stopped by killing them
+func startInGroup(cmd *exec.Cmd) {}
+
+// terminateGroup kills a command, as Windows has no signal asking it to terminate
+func terminateGroup(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
+
+// killGroup kills a command
+func killGroup(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
@@ -1,26 +1,300 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-env-cli/internal/pkg/db"
+	"go-env-cli/internal/pkg/logger"
 
 	"github.com/spf13/viper"
 )
 
+// FileName is the name of the configuration file, looked up in the current
+// directory and then in the home directory
+const FileName = ".go-env-cli.yaml"
+
+// EnvVar is the environment variable read for the configuration file path when no
+// --config flag is given
+const EnvVar = "GO_ENV_CLI_CONFIG"
+
+// ProfileEnvVar is the environment variable read for the profile when no --profile
+// flag is given
+const ProfileEnvVar = "GO_ENV_CLI_PROFILE"
+
 // Config holds all configuration for the application
 type Config struct {
-	GO_CLI_DB string `mapstructure:"go_cli_db"`
+	GO_CLI_DB string         `mapstructure:"go_cli_db"`
+	Database  DatabaseConfig `mapstructure:"database"`
+
+	// Named database targets. The settings of the active profile override those
+	// under database.
+	Profiles map[string]DatabaseConfig `mapstructure:"profiles"`
+
+	// Profile is the active profile, empty when none is used
+	Profile string `mapstructure:"profile"`
+
+	// File is the configuration file that was read, empty when none was found
+	File string `mapstructure:"-"`
 }
 
-// LoadConfig loads configuration from file, environment variables or defaults
-func LoadConfig() (*Config, error) {
+// DatabaseConfig holds the individual database connection settings written by
+// `config init`. They are only used when GO_CLI_DB is not set.
+type DatabaseConfig struct {
+	Host     string `mapstructure:"host" yaml:"host"`
+	Port     int    `mapstructure:"port" yaml:"port"`
+	User     string `mapstructure:"user" yaml:"user"`
+	Password string `mapstructure:"password" yaml:"password"`
+	DBName   string `mapstructure:"dbname" yaml:"dbname"`
+	SSLMode  string `mapstructure:"sslmode" yaml:"sslmode"`
+
+	// TLS files, also applied when the connection comes from GO_CLI_DB
+	SSLRootCert string `mapstructure:"sslrootcert" yaml:"sslrootcert,omitempty"`
+	SSLCert     string `mapstructure:"sslcert" yaml:"sslcert,omitempty"`
+	SSLKey      string `mapstructure:"sslkey" yaml:"sslkey,omitempty"`
+
+	// Connection pool limits
+	MaxOpenConns    int           `mapstructure:"max_open_conns" yaml:"max_open_conns,omitempty"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns" yaml:"max_idle_conns,omitempty"`
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime" yaml:"conn_max_lifetime,omitempty"`
+
+	// Query logging and the duration after which a query is logged as slow
+	DebugSQL           bool          `mapstructure:"debug_sql" yaml:"debug_sql,omitempty"`
+	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold" yaml:"slow_query_threshold,omitempty"`
+}
+
+// DSN returns the PostgreSQL connection URL for the settings
+func (d DatabaseConfig) DSN() string {
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(d.User, d.Password),
+		Host:   net.JoinHostPort(d.Host, strconv.Itoa(d.Port)),
+		Path:   "/" + d.DBName,
+	}
+	if d.SSLMode != "" {
+		u.RawQuery = url.Values{"sslmode": {d.SSLMode}}.Encode()
+	}
+	return u.String()
+}
+
+// ErrInvalidConfig is wrapped by the errors LoadConfig returns for an unreadable configuration
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// ExplicitPath returns the configuration file chosen with configPath (the --config
+// flag) or, when that is empty, the GO_ENV_CLI_CONFIG environment variable. It is empty
+// when neither is set and the file is looked up by name.
+func ExplicitPath(configPath string) string {
+	if configPath != "" {
+		return configPath
+	}
+	return os.Getenv(EnvVar)
+}
+
+// LoadConfig loads configuration from file, environment variables or defaults. The
+// file is configPath, or GO_ENV_CLI_CONFIG when configPath is empty, and must exist
+// when given; otherwise FileName is looked up in the current and home directories.
+// The active profile is profile, or GO_ENV_CLI_PROFILE, or the file's default profile;
+// its connection settings take precedence over GO_CLI_DB.
+func LoadConfig(configPath, profile string) (*Config, error) {
 	var config Config
 
+	viper.SetConfigType("yaml")
+	if path := ExplicitPath(configPath); path != "" {
+		viper.SetConfigFile(path)
+	} else {
+		viper.SetConfigName(".go-env-cli")
+		viper.AddConfigPath(".")
+		if home, err := os.UserHomeDir(); err == nil {
+			viper.AddConfigPath(home)
+		}
+	}
+
+	if err := viper.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if ExplicitPath(configPath) != "" || !errors.As(err, &notFound) {
+			return nil, fmt.Errorf("%w: error reading config file: %w", ErrInvalidConfig, err)
+		}
+		logger.Debugf("No %s found, using environment variables", FileName)
+	} else {
+		logger.Debugf("Using config file %s", viper.ConfigFileUsed())
+		config.File = viper.ConfigFileUsed()
+	}
+
 	viper.AutomaticEnv()
 	viper.BindEnv("go_cli_db", "GO_CLI_DB")
+	viper.BindEnv("database.sslrootcert", "GO_CLI_DB_SSLROOTCERT")
+	viper.BindEnv("database.sslcert", "GO_CLI_DB_SSLCERT")
+	viper.BindEnv("database.sslkey", "GO_CLI_DB_SSLKEY")
+	viper.BindEnv("database.max_open_conns", "GO_CLI_DB_MAX_OPEN_CONNS")
+	viper.BindEnv("database.max_idle_conns", "GO_CLI_DB_MAX_IDLE_CONNS")
+	viper.BindEnv("database.conn_max_lifetime", "GO_CLI_DB_CONN_MAX_LIFETIME")
+	viper.BindEnv("database.debug_sql", "GO_CLI_DB_DEBUG_SQL")
+	viper.BindEnv("database.slow_query_threshold", "GO_CLI_DB_SLOW_QUERY_THRESHOLD")
+
+	viper.SetDefault("database.max_open_conns", db.DefaultMaxOpenConns)
+	viper.SetDefault("database.max_idle_conns", db.DefaultMaxIdleConns)
+	viper.SetDefault("database.conn_max_lifetime", db.DefaultConnMaxLifetime)
+	viper.SetDefault("database.slow_query_threshold", db.DefaultSlowQueryThreshold)
+
+	// Apply the settings of the active profile over the database section
+	if profile == "" {
+		profile = os.Getenv(ProfileEnvVar)
+	}
+	if profile == "" {
+		profile = viper.GetString("profile")
+	}
+	if profile != "" {
+		settings, err := profileSettings(profile)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range settings {
+			viper.Set("database."+key, value)
+		}
+		logger.Debugf("Using profile %s", profile)
+	}
 
 	if err := viper.Unmarshal(&config); err != nil {
-		return nil, fmt.Errorf("error unmarshalling config: %w", err)
+		return nil, fmt.Errorf("%w: error unmarshalling config: %w", ErrInvalidConfig, err)
+	}
+	config.Profile = profile
+
+	// Fall back to the connection settings from the config file, which a profile
+	// chooses explicitly
+	if (config.GO_CLI_DB == "" || profile != "") && config.Database.Host != "" {
+		config.GO_CLI_DB = config.Database.DSN()
 	}
 
 	return &config, nil
 }
+
+// SSLModes are the sslmode values PostgreSQL accepts
+var SSLModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// Validate checks the settings before they are used to connect, returning an error
+// matching ErrInvalidConfig that lists every problem found. The port, user, database
+// name and sslmode are checked in the database section when the connection comes
+// from it; a GO_CLI_DB connection string only has its port and sslmode checked, as
+// the driver fills in a missing user and database name, like all of the settings
+// from the PG* environment variables when there is no connection string at all.
+func (c *Config) Validate() error {
+	var problems []string
+	if c.Database.Host != "" && c.GO_CLI_DB == c.Database.DSN() {
+		d := c.Database
+		if d.Port < 1 || d.Port > 65535 {
+			problems = append(problems, fmt.Sprintf("database.port must be 1-65535, got %d", d.Port))
+		}
+		if d.User == "" {
+			problems = append(problems, "database.user must not be empty")
+		}
+		if d.DBName == "" {
+			problems = append(problems, "database.dbname must not be empty")
+		}
+		if d.SSLMode != "" && !isSSLMode(d.SSLMode) {
+			problems = append(problems, fmt.Sprintf("database.sslmode must be one of %s, got '%s'", strings.Join(SSLModes, ", "), d.SSLMode))
+		}
+	} else {
+		port, sslMode := connectionParams(c.GO_CLI_DB)
+		if port != "" {
+			if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+				problems = append(problems, fmt.Sprintf("GO_CLI_DB port must be 1-65535, got '%s'", port))
+			}
+		}
+		if sslMode != "" && !isSSLMode(sslMode) {
+			problems = append(problems, fmt.Sprintf("GO_CLI_DB sslmode must be one of %s, got '%s'", strings.Join(SSLModes, ", "), sslMode))
+		}
+	}
+
+	for _, setting := range []struct {
+		name     string
+		negative bool
+	}{
+		{"database.max_open_conns", c.Database.MaxOpenConns < 0},
+		{"database.max_idle_conns", c.Database.MaxIdleConns < 0},
+		{"database.conn_max_lifetime", c.Database.ConnMaxLifetime < 0},
+		{"database.slow_query_threshold", c.Database.SlowQueryThreshold < 0},
+	} {
+		if setting.negative {
+			problems = append(problems, setting.name+" must not be negative")
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidConfig, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// isSSLMode reports whether mode is one of SSLModes
+func isSSLMode(mode string) bool {
+	for _, m := range SSLModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// connectionParams returns the port and sslmode of a postgres:// URL or a key=value
+// connection string, empty when not set
+func connectionParams(dsn string) (port, sslMode string) {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return "", ""
+		}
+		return u.Port(), u.Query().Get("sslmode")
+	}
+
+	for _, field := range strings.Fields(dsn) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, "'")
+		switch key {
+		case "port":
+			port = value
+		case "sslmode":
+			sslMode = value
+		}
+	}
+	return port, sslMode
+}
+
+// profileSettings returns the settings of a profile in the configuration file
+func profileSettings(name string) (map[string]interface{}, error) {
+	if strings.Contains(name, ".") {
+		return nil, fmt.Errorf("%w: invalid profile name '%s'", ErrInvalidConfig, name)
+	}
+
+	key := "profiles." + strings.ToLower(name)
+	if !viper.IsSet(key) {
+		return nil, fmt.Errorf("%w: unknown profile '%s'", ErrInvalidConfig, name)
+	}
+
+	return viper.GetStringMap(key), nil
+}
+
+// DB returns the settings for connecting to the database
+func (c *Config) DB() db.Config {
+	return db.Config{
+		GO_CLI_DB:   c.GO_CLI_DB,
+		SSLRootCert: c.Database.SSLRootCert,
+		SSLCert:     c.Database.SSLCert,
+		SSLKey:      c.Database.SSLKey,
+
+		MaxOpenConns:    c.Database.MaxOpenConns,
+		MaxIdleConns:    c.Database.MaxIdleConns,
+		ConnMaxLifetime: c.Database.ConnMaxLifetime,
+
+		DebugSQL:           c.Database.DebugSQL,
+		SlowQueryThreshold: c.Database.SlowQueryThreshold,
+	}
+}
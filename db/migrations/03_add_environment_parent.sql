@@ -0,0 +1,3 @@
+-- Allow an environment to inherit variables from a parent environment
+
+ALTER TABLE environments ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES environments(id);
@@ -0,0 +1,11 @@
+-- Declared value types of a project's variables, checked whenever a value is written
+
+CREATE TABLE IF NOT EXISTS variable_types (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id),
+    key VARCHAR(255) NOT NULL,
+    type TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (project_id, key)
+);
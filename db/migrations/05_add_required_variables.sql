@@ -0,0 +1,3 @@
+-- Allow marking a project's variables as required in every environment
+
+ALTER TABLE variable_types ADD COLUMN IF NOT EXISTS required BOOLEAN NOT NULL DEFAULT FALSE;
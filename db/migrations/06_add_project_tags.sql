@@ -0,0 +1,10 @@
+-- Free-form labels on projects, such as team:payments or tier:critical
+
+CREATE TABLE IF NOT EXISTS project_tags (
+    project_id UUID NOT NULL REFERENCES projects(id),
+    tag VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (project_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_tags_tag ON project_tags (tag);
@@ -0,0 +1,15 @@
+-- Make environment names unique regardless of case, so "Production" and "production"
+-- can't both exist
+
+-- Rename the duplicates already there: the oldest environment of each name keeps it,
+-- the others get the start of their ID appended (e.g. "Production-1a2b3c4d"), so no
+-- variable is lost and each can still be told apart
+UPDATE environments e
+SET name = LEFT(e.name, 41) || '-' || LEFT(e.id::text, 8), updated_at = NOW()
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY LOWER(name) ORDER BY created_at, id) AS n
+    FROM environments
+) ranked
+WHERE ranked.id = e.id AND ranked.n > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_environments_name_lower ON environments (LOWER(name));
@@ -0,0 +1,19 @@
+-- Point-in-time copies of every variable of a project environment, for rolling the
+-- whole environment back at once
+
+CREATE TABLE IF NOT EXISTS env_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id),
+    environment_id UUID NOT NULL REFERENCES environments(id),
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_env_snapshots_project_environment ON env_snapshots (project_id, environment_id);
+
+CREATE TABLE IF NOT EXISTS env_snapshot_variables (
+    snapshot_id BIGINT NOT NULL REFERENCES env_snapshots(id) ON DELETE CASCADE,
+    key VARCHAR(255) NOT NULL,
+    value TEXT,
+    PRIMARY KEY (snapshot_id, key)
+);
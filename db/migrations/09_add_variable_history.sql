@@ -0,0 +1,17 @@
+-- Every set, delete and rename of a variable, with the values before and after, so
+-- the most recent change of an environment can be undone
+
+CREATE TABLE IF NOT EXISTS variable_history (
+    id BIGSERIAL PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id),
+    environment_id UUID NOT NULL REFERENCES environments(id),
+    operation VARCHAR(16) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    old_key VARCHAR(255),
+    old_value TEXT,
+    new_value TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    undone_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_variable_history_project_environment ON variable_history (project_id, environment_id, id);
@@ -0,0 +1,7 @@
+-- Indexes for the lookups nearly every command makes: variables by project,
+-- environment and key, and projects by name among the active (not deleted) ones
+
+CREATE INDEX IF NOT EXISTS idx_env_variables_project_environment_key ON env_variables (project_id, environment_id, key);
+
+-- Keeps the application-level uniqueness check of active project names fast
+CREATE INDEX IF NOT EXISTS idx_projects_active_name ON projects (name) WHERE deleted_at IS NULL;
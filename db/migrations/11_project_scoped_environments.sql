@@ -0,0 +1,55 @@
+-- Make environments belong to a project. Environments without a project remain as
+-- shared templates: a project gets its own copy of one the first time it uses it.
+
+ALTER TABLE environments ADD COLUMN IF NOT EXISTS project_id UUID REFERENCES projects(id);
+
+-- Names are now unique per project (and among the shared environments)
+DROP INDEX IF EXISTS idx_environments_name_lower;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_environments_project_name_lower
+    ON environments (COALESCE(project_id, '00000000-0000-0000-0000-000000000000'::uuid), LOWER(name));
+
+-- Back-fill: one copy of each shared environment for every project that uses it,
+-- directly or as the ancestor of one it uses
+CREATE TEMPORARY TABLE environment_copies ON COMMIT DROP AS
+WITH RECURSIVE used (project_id, shared_id) AS (
+    SELECT project_id, environment_id FROM env_variables
+    UNION
+    SELECT project_id, environment_id FROM env_snapshots
+    UNION
+    SELECT project_id, environment_id FROM variable_history
+    UNION
+    SELECT used.project_id, e.parent_id
+    FROM used
+    JOIN environments e ON e.id = used.shared_id
+    WHERE e.parent_id IS NOT NULL
+)
+SELECT used.project_id, used.shared_id, gen_random_uuid() AS id
+FROM used
+JOIN environments e ON e.id = used.shared_id AND e.project_id IS NULL;
+
+INSERT INTO environments (id, project_id, name, description, created_at, updated_at)
+SELECT c.id, c.project_id, e.name, e.description, e.created_at, e.updated_at
+FROM environment_copies c
+JOIN environments e ON e.id = c.shared_id;
+
+-- Copies inherit from the same project's copy of the parent
+UPDATE environments env
+SET parent_id = parent_copy.id
+FROM environment_copies c
+JOIN environments shared ON shared.id = c.shared_id
+JOIN environment_copies parent_copy ON parent_copy.shared_id = shared.parent_id AND parent_copy.project_id = c.project_id
+WHERE env.id = c.id;
+
+UPDATE env_variables v SET environment_id = c.id
+FROM environment_copies c
+WHERE v.project_id = c.project_id AND v.environment_id = c.shared_id;
+
+UPDATE env_snapshots s SET environment_id = c.id
+FROM environment_copies c
+WHERE s.project_id = c.project_id AND s.environment_id = c.shared_id;
+
+UPDATE variable_history h SET environment_id = c.id
+FROM environment_copies c
+WHERE h.project_id = c.project_id AND h.environment_id = c.shared_id;
+
+CREATE INDEX IF NOT EXISTS idx_environments_project ON environments (project_id);
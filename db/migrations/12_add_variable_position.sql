@@ -0,0 +1,3 @@
+-- Remember the order of variables in the file they were last imported from
+
+ALTER TABLE env_variables ADD COLUMN IF NOT EXISTS position INTEGER;
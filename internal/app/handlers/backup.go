@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"go-env-cli/internal/app/models"
+)
+
+// BackupToFile writes a JSON backup of the whole database to filePath. progress is
+// told about the steps of reading the database, and then of writing the file.
+func (h *EnvHandler) BackupToFile(filePath string, progress models.ProgressFunc) (*models.Backup, error) {
+	steps := models.DumpSteps + 1
+	backup, err := h.repo.Dump(func(done, _ int) {
+		progress.Report(done, steps)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read database: %w", err)
+	}
+
+	// Backups contain every secret, so write them like exported env files
+	err = writeFileAtomic(filePath, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(backup)
+	})
+	if err != nil {
+		return nil, err
+	}
+	progress.Report(steps, steps)
+
+	return backup, nil
+}
+
+// RestoreFromFile restores a JSON backup written by BackupToFile. progress is told
+// about every variable restored.
+func (h *EnvHandler) RestoreFromFile(filePath string, progress models.ProgressFunc) (*models.Backup, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup file: %w", err)
+	}
+	defer file.Close()
+
+	backup := &models.Backup{}
+	if err := json.NewDecoder(file).Decode(backup); err != nil {
+		return nil, fmt.Errorf("failed to parse backup file: %w", err)
+	}
+
+	if err := h.repo.Restore(backup, progress); err != nil {
+		return nil, fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	return backup, nil
+}
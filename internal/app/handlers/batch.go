@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"fmt"
+
+	"go-env-cli/internal/app/models"
+)
+
+// BatchOperation is one line of a batch file: a set, delete or rename-var command with
+// its flags
+type BatchOperation struct {
+	Line        int
+	Command     string // "set", "delete" or "rename-var"
+	Project     string
+	Environment string
+	Key         string
+	Value       string
+	NewKey      string
+}
+
+// batchOperations maps the commands allowed in a batch file to the change they make
+var batchOperations = map[string]string{
+	"set":        models.HistorySet,
+	"delete":     models.HistoryDelete,
+	"rename-var": models.HistoryRename,
+}
+
+// ApplyBatch resolves the projects and environments of a batch of operations, checks
+// the values against their declared types and applies every operation in a single
+// transaction, which also gives projects their copies of the shared environments
+// changed. Errors name the line of the failing operation, and nothing is changed when
+// one fails.
+func (h *EnvHandler) ApplyBatch(operations []BatchOperation) error {
+	projects := make(map[string]*models.Project)
+	// Environments are cached per project and environment name
+	environments := make(map[[2]string]*models.Environment)
+
+	changes := make([]models.BatchChange, 0, len(operations))
+	for _, op := range operations {
+		operation, ok := batchOperations[op.Command]
+		if !ok {
+			return fmt.Errorf("line %d: unknown command '%s', expected set, delete or rename-var", op.Line, op.Command)
+		}
+
+		project, ok := projects[op.Project]
+		if !ok {
+			var err error
+			if project, err = h.findProject(op.Project); err != nil {
+				return fmt.Errorf("line %d: %w", op.Line, err)
+			}
+			projects[op.Project] = project
+		}
+
+		env, ok := environments[[2]string{op.Project, op.Environment}]
+		if !ok {
+			var err error
+			if env, err = h.findEnvironment(project, op.Environment); err != nil {
+				return fmt.Errorf("line %d: %w", op.Line, err)
+			}
+			environments[[2]string{op.Project, op.Environment}] = env
+		}
+
+		if operation == models.HistorySet {
+			if err := h.validateValues(project.ID, map[string]string{op.Key: op.Value}); err != nil {
+				return fmt.Errorf("line %d: %w", op.Line, err)
+			}
+		}
+
+		changes = append(changes, models.BatchChange{
+			Line:          op.Line,
+			ProjectID:     project.ID,
+			EnvironmentID: env.ID,
+			Operation:     operation,
+			Key:           op.Key,
+			Value:         op.Value,
+			NewKey:        op.NewKey,
+		})
+	}
+
+	return h.repo.ApplyBatch(changes)
+}
@@ -1,273 +1,1273 @@
 package handlers
 
 import (
-	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/user"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"go-env-cli/internal/app/models"
+	"go-env-cli/internal/pkg/logger"
+	"go-env-cli/internal/pkg/utils"
+
+	"github.com/google/uuid"
 )
 
-// EnvHandler handles environment variable operations
-type EnvHandler struct {
-	repo *models.Repository
-}
+// EnvHandler handles environment variable operations
+type EnvHandler struct {
+	repo *models.Repository
+}
+
+// NewEnvHandler creates a new environment handler
+func NewEnvHandler(repo *models.Repository) *EnvHandler {
+	return &EnvHandler{repo: repo}
+}
+
+// ImportOptions controls how variables are stored by ImportEnvFile
+type ImportOptions struct {
+	// Format selects the input format, defaulting to FormatDotenv when empty
+	Format string
+
+	// AddPrefix is prepended to every imported key
+	AddPrefix string
+
+	// Upper uppercases every key before storing it
+	Upper bool
+
+	// WarnSecrets logs a warning for every value that looks like a real credential
+	WarnSecrets bool
+
+	// NoTrim keeps the whitespace around unquoted .env values instead of trimming it
+	NoTrim bool
+
+	// StrictDuplicates fails the import when the file sets a key more than once,
+	// instead of warning and keeping the last value
+	StrictDuplicates bool
+
+	// Parallel stores the variables in this many batches written concurrently, each in
+	// its own transaction, instead of a single transaction when it is 0 or 1
+	Parallel int
+
+	// Progress is told about every variable stored
+	Progress models.ProgressFunc
+}
+
+// ImportEnvFile imports environment variables from a .env file
+func (h *EnvHandler) ImportEnvFile(filePath, projectName, environmentName string, opts ImportOptions) (*models.ImportSummary, error) {
+	// Open the .env file
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open env file: %w", err)
+	}
+	defer file.Close()
+
+	return h.ImportEnv(file, filePath, projectName, environmentName, opts)
+}
+
+// ImportEnv imports environment variables read from r in the format selected by opts.
+// source describes where the content came from and is used in descriptions of
+// projects created by the import. Variables are stored in environmentName unless the
+// input names their environment, as a CSV environment column does. All variables are
+// stored in a single transaction, unless opts.Parallel splits them into concurrent
+// batches, and the returned summary counts what happened to them.
+func (h *EnvHandler) ImportEnv(r io.Reader, source, projectName, environmentName string, opts ImportOptions) (*models.ImportSummary, error) {
+	if err := ValidateImportFormat(opts.Format); err != nil {
+		return nil, err
+	}
+
+	// Read the whole input so it can be backed up
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+
+	// Parse and validate the whole input before creating anything, so a bad file
+	// leaves no empty project or environment behind
+	entries, err := parseImportEntries(content, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check every value against the declared types before writing any. A project
+	// that doesn't exist yet has no declared types.
+	project, err := h.repo.GetProjectByName(projectName)
+	if err != nil && !errors.Is(err, models.ErrProjectNotFound) {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	if project != nil {
+		types, err := h.valueTypes(project.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if valueType, ok := types[entry.Key]; ok {
+				if err := valueType.Validate(entry.Key, entry.Value); err != nil {
+					return nil, fmt.Errorf("invalid value at line %d: %w", entry.Line, err)
+				}
+			}
+		}
+	}
+
+	if opts.WarnSecrets {
+		for _, entry := range entries {
+			WarnIfSecret(entry.Key, entry.Value)
+		}
+	}
+
+	for _, d := range utils.FindDuplicateKeys(entries) {
+		logger.Warnf("duplicate key %s, keeping the value of line %d", d, d.Lines[len(d.Lines)-1])
+	}
+
+	// Create the project if it doesn't exist
+	if project == nil {
+		project, err = h.repo.CreateProject(projectName, fmt.Sprintf("Project created from env file import: %s", source))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create project: %w", err)
+		}
+	}
+
+	// Get or create environment
+	env, err := h.ensureEnvironment(project, environmentName, fmt.Sprintf("Environment created for project: %s", projectName))
+	if err != nil {
+		return nil, err
+	}
+	environments := map[string]*models.Environment{environmentName: env}
+
+	// Create a backup of the .env content
+	if err := createEnvBackup(content, projectName); err != nil {
+		return nil, fmt.Errorf("failed to create backup: %w", err)
+	}
+
+	// Only the last entry for a key of an environment is stored, at the position of
+	// its first entry, which export --order original reproduces
+	var variables []models.EnvVariable
+	indexes := make(map[[2]string]int)
+	positions := make(map[string]int)
+	skipped := 0
+	for _, entry := range entries {
+		// Get or create the environment named by the entry
+		name := entry.Environment
+		if name == "" {
+			name = environmentName
+		}
+		env, ok := environments[name]
+		if !ok {
+			env, err = h.ensureEnvironment(project, name, fmt.Sprintf("Environment created for project: %s", projectName))
+			if err != nil {
+				return nil, err
+			}
+			environments[name] = env
+		}
+
+		if i, ok := indexes[[2]string{name, entry.Key}]; ok {
+			variables[i].Value = entry.Value
+			skipped++
+			continue
+		}
+
+		position := positions[name]
+		positions[name]++
+		indexes[[2]string{name, entry.Key}] = len(variables)
+		variables = append(variables, models.EnvVariable{EnvironmentID: env.ID, Key: entry.Key, Value: entry.Value, Position: &position})
+	}
+
+	// Save to database
+	summary, err := h.repo.SetEnvVariablesParallel(project.ID, variables, opts.Parallel, opts.Progress)
+	if err != nil {
+		return nil, err
+	}
+	summary.Skipped = skipped
+
+	return summary, nil
+}
+
+// PreviewImport reports the existing variables an import of the content read from r
+// would change, without changing anything. Keys that don't exist yet are left out, as
+// are keys whose value would stay the same.
+func (h *EnvHandler) PreviewImport(r io.Reader, projectName, environmentName string, opts ImportOptions) ([]utils.EnvChange, error) {
+	if err := ValidateImportFormat(opts.Format); err != nil {
+		return nil, err
+	}
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+
+	entries, err := parseImportEntries(content, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// A project that doesn't exist yet has nothing to overwrite
+	project, err := h.repo.GetProjectByName(projectName)
+	if errors.Is(err, models.ErrProjectNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	// Current values of each environment named by the entries, later entries winning
+	current := make(map[string]map[string]string)
+	incoming := make(map[string]map[string]string)
+	for _, entry := range entries {
+		name := entry.Environment
+		if name == "" {
+			name = environmentName
+		}
+
+		if _, ok := current[name]; !ok {
+			values := map[string]string{}
+			env, err := h.repo.GetEnvironmentByName(&project.ID, name)
+			if err != nil && !errors.Is(err, models.ErrEnvironmentNotFound) {
+				return nil, fmt.Errorf("failed to get environment: %w", err)
+			}
+			if err == nil {
+				variables, err := h.repo.GetEnvVariables(project.ID, env.ID)
+				if err != nil {
+					return nil, fmt.Errorf("failed to list environment variables: %w", err)
+				}
+				for _, v := range variables {
+					values[v.Key] = v.Value
+				}
+			}
+			current[name] = values
+			incoming[name] = map[string]string{}
+		}
+
+		incoming[name][entry.Key] = entry.Value
+	}
+
+	var changes []utils.EnvChange
+	for name, values := range incoming {
+		for _, c := range utils.DiffEnv(current[name], values).Changed {
+			if name != environmentName {
+				c.Key = name + "/" + c.Key
+			}
+			changes = append(changes, c)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes, nil
+}
+
+// parseImportEntries parses content in the import format of opts and applies its key
+// transformations
+func parseImportEntries(content []byte, opts ImportOptions) ([]utils.EnvEntry, error) {
+	entries, err := parseImport(content, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Upper {
+		if entries, err = utils.UppercaseKeys(entries); err != nil {
+			return nil, err
+		}
+	}
+	for i := range entries {
+		entries[i].Key = opts.AddPrefix + entries[i].Key
+		if err := utils.ValidateKey(entries[i].Key); err != nil {
+			return nil, fmt.Errorf("line %d: %w", entries[i].Line, err)
+		}
+	}
+
+	if duplicates := utils.FindDuplicateKeys(entries); len(duplicates) > 0 && opts.StrictDuplicates {
+		described := make([]string, len(duplicates))
+		for i, d := range duplicates {
+			described[i] = d.String()
+		}
+		return nil, fmt.Errorf("%w: %s", utils.ErrDuplicateKeys, strings.Join(described, ", "))
+	}
+
+	return entries, nil
+}
+
+// parseImport parses content in the import format of opts. Entries that don't name an
+// environment are left with an empty Environment.
+func parseImport(content []byte, opts ImportOptions) ([]utils.EnvEntry, error) {
+	content = utils.NormalizeNewlines(content)
+	if opts.Format == FormatCSV {
+		return utils.ParseCSV(bytes.NewReader(content))
+	}
+	return utils.ParseEnvWith(bytes.NewReader(content), utils.ParseOptions{NoTrim: opts.NoTrim})
+}
+
+// ExportEnvFile exports environment variables to a .env file
+func (h *EnvHandler) ExportEnvFile(filePath, projectName, environmentName string, opts ExportOptions) error {
+	if err := ValidateExportFormat(opts.Format); err != nil {
+		return err
+	}
+	if err := ValidateExportOrder(opts.Order); err != nil {
+		return err
+	}
+
+	// Load variables before touching the file system
+	variables, environmentName, err := h.exportVariables(projectName, environmentName, opts)
+	if err != nil {
+		return err
+	}
+
+	// Write the file atomically so a failure never leaves a truncated file behind
+	return writeFileAtomic(filePath, func(w io.Writer) error {
+		return renderExport(w, projectName, environmentName, variables, opts)
+	})
+}
+
+// ExportEnv writes environment variables to w
+func (h *EnvHandler) ExportEnv(w io.Writer, projectName, environmentName string, opts ExportOptions) error {
+	if err := ValidateExportFormat(opts.Format); err != nil {
+		return err
+	}
+	if err := ValidateExportOrder(opts.Order); err != nil {
+		return err
+	}
+
+	variables, environmentName, err := h.exportVariables(projectName, environmentName, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := renderExport(w, projectName, environmentName, variables, opts); err != nil {
+		return fmt.Errorf("failed to write env file: %w", err)
+	}
+
+	return nil
+}
+
+// renderExport renders variables like renderVariables, encrypting the output when
+// opts.EncryptTo names a recipient
+func renderExport(w io.Writer, projectName, environmentName string, variables []models.EnvVariable, opts ExportOptions) error {
+	if opts.EncryptTo == "" {
+		return renderVariables(w, projectName, environmentName, variables, opts)
+	}
+
+	var plaintext bytes.Buffer
+	if err := renderVariables(&plaintext, projectName, environmentName, variables, opts); err != nil {
+		return err
+	}
+
+	ciphertext, err := utils.Encrypt(plaintext.Bytes(), opts.EncryptTo)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt export: %w", err)
+	}
+
+	_, err = w.Write(ciphertext)
+	return err
+}
+
+// exportVariables loads the variables of an environment merged with the overlay
+// environments of opts, limited to the keys of opts and without its excluded keys, and
+// returns the name of the environment the result is named after
+func (h *EnvHandler) exportVariables(projectName, environmentName string, opts ExportOptions) ([]models.EnvVariable, string, error) {
+	variables, err := h.ListEnvVariables(projectName, environmentName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(opts.Overlays) > 0 {
+		if variables, err = h.mergeOverlays(projectName, variables, opts.Overlays); err != nil {
+			return nil, "", err
+		}
+		environmentName = opts.Overlays[len(opts.Overlays)-1]
+	}
+
+	if variables, err = filterVariables(variables, opts); err != nil {
+		return nil, "", err
+	}
+
+	return variables, environmentName, nil
+}
+
+// mergeOverlays merges the variables of the overlay environments over variables, later
+// ones winning
+func (h *EnvHandler) mergeOverlays(projectName string, variables []models.EnvVariable, overlays []string) ([]models.EnvVariable, error) {
+	layers := [][]models.EnvVariable{variables}
+	for _, name := range overlays {
+		overlay, err := h.ListEnvVariables(projectName, name)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, overlay)
+	}
+
+	return MergeEnvVariables(layers...), nil
+}
+
+// filterVariables keeps the variables selected by opts.Keys and drops those matching
+// opts.Exclude
+func filterVariables(variables []models.EnvVariable, opts ExportOptions) ([]models.EnvVariable, error) {
+	var err error
+	if len(opts.Keys) > 0 {
+		if variables, err = SelectKeys(variables, opts.Keys, opts.IgnoreMissing); err != nil {
+			return nil, err
+		}
+	}
+	if len(opts.Exclude) > 0 {
+		variables = ExcludeKeys(variables, opts.Exclude)
+	}
+	return variables, nil
+}
+
+// exportFileName returns the name of the file export-all writes an environment to. It
+// fails for environment names that aren't a plain file name, such as "../x", which
+// would write outside the export directory.
+func exportFileName(environmentName, format string) (string, error) {
+	if environmentName == "" || environmentName == "." || environmentName == ".." ||
+		strings.ContainsAny(environmentName, `/\`) {
+		return "", fmt.Errorf("cannot export environment '%s': its name is not a valid file name", environmentName)
+	}
+	return environmentName + FormatExtension(format), nil
+}
+
+// ExportAllEnvFiles exports every environment of a project into dir, writing one file
+// per environment named after the environment. Each environment is merged with
+// opts.Overlays and filtered by opts.Keys and opts.Exclude, like a single export.
+// Nothing is written when an environment name isn't a valid file name. progress is told
+// about every environment written. It returns the paths written.
+func (h *EnvHandler) ExportAllEnvFiles(dir, projectName string, opts ExportOptions, progress models.ProgressFunc) ([]string, error) {
+	if err := ValidateExportFormat(opts.Format); err != nil {
+		return nil, err
+	}
+	if err := ValidateExportOrder(opts.Order); err != nil {
+		return nil, err
+	}
+
+	// Check if project exists
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get environments
+	environments, err := h.repo.GetEnvironmentsForProject(project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environments for project: %w", err)
+	}
+
+	fileNames := make([]string, len(environments))
+	for i, env := range environments {
+		if fileNames[i], err = exportFileName(env.Name, opts.Format); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	var written []string
+	for i, env := range environments {
+		progress.Report(i, len(environments))
+
+		variables, err := h.repo.GetEnvVariables(project.ID, env.ID)
+		if err != nil {
+			return written, fmt.Errorf("failed to get environment variables for %s: %w", env.Name, err)
+		}
+
+		if len(opts.Overlays) > 0 {
+			if variables, err = h.mergeOverlays(projectName, variables, opts.Overlays); err != nil {
+				return written, err
+			}
+		}
+
+		if variables, err = filterVariables(variables, opts); err != nil {
+			return written, fmt.Errorf("failed to export %s environment: %w", env.Name, err)
+		}
+
+		filePath := filepath.Join(dir, fileNames[i])
+		err = writeFileAtomic(filePath, func(w io.Writer) error {
+			return renderVariables(w, projectName, env.Name, variables, opts)
+		})
+		if err != nil {
+			return written, fmt.Errorf("failed to export %s environment: %w", env.Name, err)
+		}
+
+		written = append(written, filePath)
+	}
+	progress.Report(len(environments), len(environments))
+
+	return written, nil
+}
+
+// GetProject gets an active project by name. A missing project is reported as
+// models.ErrProjectNotFound, suggesting the closest existing project name, if any.
+func (h *EnvHandler) GetProject(projectName string) (*models.Project, error) {
+	return h.findProject(projectName)
+}
+
+// ListProjects lists all projects
+func (h *EnvHandler) ListProjects() ([]models.Project, error) {
+	return h.repo.GetAllProjects()
+}
+
+// QueryProjects lists projects matching a name pattern, sorted and limited as requested
+func (h *EnvHandler) QueryProjects(q models.ProjectQuery) ([]models.Project, error) {
+	return h.repo.QueryProjects(q)
+}
+
+// SearchProjects searches for projects by name pattern
+func (h *EnvHandler) SearchProjects(pattern string) ([]models.Project, error) {
+	return h.repo.SearchProjects(pattern)
+}
+
+// SetEnvVariable sets an environment variable
+func (h *EnvHandler) SetEnvVariable(projectName, environmentName, key, value string) error {
+	// Check if project exists
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	// Get environment
+	env, err := h.writableEnvironment(project, environmentName)
+	if err != nil {
+		return err
+	}
+
+	// Check the value against the declared type
+	if err := h.validateValues(project.ID, map[string]string{key: value}); err != nil {
+		return err
+	}
+
+	// Set the variable
+	_, err = h.repo.SetEnvVariable(project.ID, env.ID, key, value)
+	if err != nil {
+		return fmt.Errorf("failed to set environment variable: %w", err)
+	}
+
+	return nil
+}
+
+// ResolveKey returns the stored key of the variable matching key when case is ignored,
+// or key itself when no variable matches. It fails if several variables match, such as
+// both DB_URL and db_url.
+func (h *EnvHandler) ResolveKey(projectName, environmentName, key string) (string, error) {
+	// Check if project exists
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return "", err
+	}
+
+	// Get environment
+	env, err := h.findEnvironment(project, environmentName)
+	if err != nil {
+		return "", err
+	}
+
+	keys, err := h.repo.FindEnvVariableKeysFold(project.ID, env.ID, key)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(keys) {
+	case 0:
+		return key, nil
+	case 1:
+		return keys[0], nil
+	}
+	return "", fmt.Errorf("key '%s' matches several variables when ignoring case: %s", key, strings.Join(keys, ", "))
+}
+
+// GetEnvVariable gets an environment variable by key
+func (h *EnvHandler) GetEnvVariable(projectName, environmentName, key string) (string, error) {
+	// Check if project exists
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return "", err
+	}
+
+	// Get environment
+	env, err := h.findEnvironment(project, environmentName)
+	if err != nil {
+		return "", err
+	}
+
+	// Get the variable
+	variable, err := h.repo.GetEnvVariable(project.ID, env.ID, key)
+	if err != nil {
+		return "", err
+	}
+
+	return variable.Value, nil
+}
+
+// GetEnvVariables gets the values of several environment variables at once, keyed by
+// variable key. Keys that don't exist are absent from the result.
+func (h *EnvHandler) GetEnvVariables(projectName, environmentName string, keys []string) (map[string]string, error) {
+	// Check if project exists
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get environment
+	env, err := h.findEnvironment(project, environmentName)
+	if err != nil {
+		return nil, err
+	}
+
+	variables, err := h.repo.GetEnvVariablesByKeys(project.ID, env.ID, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environment variables: %w", err)
+	}
+
+	values := make(map[string]string, len(variables))
+	for _, v := range variables {
+		values[v.Key] = v.Value
+	}
+
+	return values, nil
+}
+
+// HasEnvVariable reports whether an environment variable exists. A missing project or
+// environment is reported as the variable not existing rather than as an error.
+func (h *EnvHandler) HasEnvVariable(projectName, environmentName, key string) (bool, error) {
+	// Check if project exists
+	project, err := h.repo.GetProjectByName(projectName)
+	if errors.Is(err, models.ErrProjectNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	// Get environment
+	env, err := h.repo.GetEnvironmentByName(&project.ID, environmentName)
+	if errors.Is(err, models.ErrEnvironmentNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get environment: %w", err)
+	}
+
+	return h.repo.EnvVariableExists(project.ID, env.ID, key)
+}
+
+// DeleteEnvVariable deletes an environment variable
+func (h *EnvHandler) DeleteEnvVariable(projectName, environmentName, key string) error {
+	// Check if project exists
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	// Get environment
+	env, err := h.findEnvironment(project, environmentName)
+	if err != nil {
+		return err
+	}
+
+	// Delete the variable
+	err = h.repo.DeleteEnvVariable(project.ID, env.ID, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete environment variable: %w", err)
+	}
+
+	return nil
+}
+
+// RenameEnvVariable renames an environment variable key. If a variable with the new key
+// already exists it is replaced when overwrite is true, otherwise an error is returned.
+func (h *EnvHandler) RenameEnvVariable(projectName, environmentName, oldKey, newKey string, overwrite bool) error {
+	// Overwriting a key with itself would delete it
+	if oldKey == newKey {
+		return fmt.Errorf("%w: can't rename '%s' to itself", utils.ErrInvalidValue, oldKey)
+	}
+
+	// Check if project exists
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	// Get environment
+	env, err := h.findEnvironment(project, environmentName)
+	if err != nil {
+		return err
+	}
+
+	// Rename the variable, replacing an existing target in the same transaction when
+	// overwriting
+	err = h.repo.RenameEnvVariable(project.ID, env.ID, oldKey, newKey, overwrite)
+	if err != nil {
+		return fmt.Errorf("failed to rename environment variable: %w", err)
+	}
+
+	return nil
+}
+
+// MoveEnvVariable moves an environment variable from one environment of a project to another
+func (h *EnvHandler) MoveEnvVariable(projectName, fromEnvironmentName, toEnvironmentName, key string, overwrite bool) error {
+	// Check if project exists
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	// Get source and destination environments
+	fromEnv, err := h.findEnvironment(project, fromEnvironmentName)
+	if err != nil {
+		return fmt.Errorf("source %w", err)
+	}
+
+	toEnv, err := h.writableEnvironment(project, toEnvironmentName)
+	if err != nil {
+		return fmt.Errorf("destination %w", err)
+	}
+
+	if fromEnv.ID == toEnv.ID {
+		return fmt.Errorf("source and destination environments are the same")
+	}
+
+	// Move the variable
+	err = h.repo.MoveEnvVariable(project.ID, fromEnv.ID, toEnv.ID, key, overwrite)
+	if err != nil {
+		return fmt.Errorf("failed to move environment variable: %w", err)
+	}
+
+	return nil
+}
+
+// CopyEnvVariable copies an environment variable from one project to another. The
+// destination environment is created if it doesn't exist.
+func (h *EnvHandler) CopyEnvVariable(fromProjectName, toProjectName, fromEnvironmentName, toEnvironmentName, key string, overwrite bool) error {
+	// Check that both projects exist
+	fromProject, err := h.findProject(fromProjectName)
+	if err != nil {
+		return fmt.Errorf("source %w", err)
+	}
+
+	toProject, err := h.findProject(toProjectName)
+	if err != nil {
+		return fmt.Errorf("destination %w", err)
+	}
+
+	// Get the source environment
+	fromEnv, err := h.findEnvironment(fromProject, fromEnvironmentName)
+	if err != nil {
+		return fmt.Errorf("source %w", err)
+	}
+
+	if fromProject.ID == toProject.ID && fromEnvironmentName == toEnvironmentName {
+		return fmt.Errorf("source and destination are the same")
+	}
+
+	// Get or create the destination environment
+	toEnv, err := h.ensureEnvironment(toProject, toEnvironmentName, fmt.Sprintf("Environment created for project: %s", toProjectName))
+	if err != nil {
+		return err
+	}
+
+	// Read the value and check it against the destination's declared type
+	variable, err := h.repo.GetEnvVariable(fromProject.ID, fromEnv.ID, key)
+	if err != nil {
+		return err
+	}
+
+	if err := h.validateValues(toProject.ID, map[string]string{key: variable.Value}); err != nil {
+		return err
+	}
+
+	// Write the copy
+	err = h.repo.CreateEnvVariable(toProject.ID, toEnv.ID, key, variable.Value, overwrite)
+	if err != nil {
+		return fmt.Errorf("failed to copy environment variable: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyEnvDiff applies the additions, changes and removals of a diff to a project
+// environment in a single transaction
+func (h *EnvHandler) ApplyEnvDiff(projectName, environmentName string, diff utils.EnvDiff) error {
+	// Check if project exists
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	// Get environment
+	env, err := h.writableEnvironment(project, environmentName)
+	if err != nil {
+		return err
+	}
+
+	sets := make(map[string]string, len(diff.Added)+len(diff.Changed))
+	for _, c := range diff.Added {
+		sets[c.Key] = c.NewValue
+	}
+	for _, c := range diff.Changed {
+		sets[c.Key] = c.NewValue
+	}
+
+	if err := h.validateValues(project.ID, sets); err != nil {
+		return err
+	}
+
+	deletes := make([]string, 0, len(diff.Removed))
+	for _, c := range diff.Removed {
+		deletes = append(deletes, c.Key)
+	}
+
+	if err := h.repo.ApplyEnvChanges(project.ID, env.ID, sets, deletes); err != nil {
+		return fmt.Errorf("failed to apply changes: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteEnvVariablesByPattern deletes all environment variables whose key matches a glob
+// pattern, ignoring case, as matched by utils.Glob
+func (h *EnvHandler) DeleteEnvVariablesByPattern(projectName, environmentName, pattern string) (int64, error) {
+	// Check if project exists
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return 0, err
+	}
+
+	// Get environment
+	env, err := h.findEnvironment(project, environmentName)
+	if err != nil {
+		return 0, err
+	}
+
+	// Delete the matching variables
+	count, err := h.repo.DeleteEnvVariablesByPattern(project.ID, env.ID, utils.GlobToLike(pattern))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete environment variables: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListEnvVariables lists all environment variables for a project and environment
+func (h *EnvHandler) ListEnvVariables(projectName, environmentName string) ([]models.EnvVariable, error) {
+	// Check if project exists
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get environment
+	env, err := h.findEnvironment(project, environmentName)
+	if err != nil {
+		return nil, err
+	}
 
-// NewEnvHandler creates a new environment handler
-func NewEnvHandler(repo *models.Repository) *EnvHandler {
-	return &EnvHandler{repo: repo}
+	// Get variables
+	variables, err := h.repo.GetEnvVariables(project.ID, env.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environment variables: %w", err)
+	}
+
+	return variables, nil
 }
 
-// ImportEnvFile imports environment variables from a .env file
-func (h *EnvHandler) ImportEnvFile(filePath, projectName, environmentName string) error {
-	// Check if project exists, create if not
-	project, err := h.repo.GetProjectByName(projectName)
+// ListEnvVariablesPage lists a page of the environment variables of a project
+// environment ordered by key, and returns the total number of variables
+func (h *EnvHandler) ListEnvVariablesPage(projectName, environmentName string, limit, offset int) ([]models.EnvVariable, int, error) {
+	// Check if project exists
+	project, err := h.findProject(projectName)
 	if err != nil {
-		// Project doesn't exist, create it
-		project, err = h.repo.CreateProject(projectName, fmt.Sprintf("Project created from env file import: %s", filePath))
-		if err != nil {
-			return fmt.Errorf("failed to create project: %w", err)
-		}
+		return nil, 0, err
 	}
 
-	// Get or create environment
-	env, err := h.repo.GetEnvironmentByName(environmentName)
+	// Get environment
+	env, err := h.findEnvironment(project, environmentName)
 	if err != nil {
-		// Environment doesn't exist, create it
-		env, err = h.repo.CreateEnvironment(environmentName, fmt.Sprintf("Environment created for project: %s", projectName))
-		if err != nil {
-			return fmt.Errorf("failed to create environment: %w", err)
-		}
+		return nil, 0, err
+	}
+
+	// Get variables
+	variables, total, err := h.repo.GetEnvVariablesPage(project.ID, env.ID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list environment variables: %w", err)
 	}
 
-	// Create a backup of the .env file
-	if err := createEnvBackup(filePath, projectName); err != nil {
-		return fmt.Errorf("failed to create backup: %w", err)
+	return variables, total, nil
+}
+
+// ListEnvVariablesSince lists the environment variables of a project environment
+// updated at or after updatedSince and created at or after createdSince. Pass the zero
+// time to skip either condition.
+func (h *EnvHandler) ListEnvVariablesSince(projectName, environmentName string, updatedSince, createdSince time.Time) ([]models.EnvVariable, error) {
+	// Check if project exists
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return nil, err
 	}
 
-	// Open and parse .env file
-	file, err := os.Open(filePath)
+	// Get environment
+	env, err := h.findEnvironment(project, environmentName)
 	if err != nil {
-		return fmt.Errorf("failed to open env file: %w", err)
+		return nil, err
 	}
-	defer file.Close()
 
-	// Read the file line by line
-	scanner := bufio.NewScanner(file)
-	lineNumber := 0
-	for scanner.Scan() {
-		lineNumber++
-		line := strings.TrimSpace(scanner.Text())
+	variables, err := h.repo.GetEnvVariablesSince(project.ID, env.ID, updatedSince, createdSince)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environment variables: %w", err)
+	}
 
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
-			continue
-		}
+	return variables, nil
+}
 
-		// Parse key-value pairs
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			return fmt.Errorf("invalid format at line %d: %s", lineNumber, line)
+// FilterEnvVariablesSince returns the variables updated at or after updatedSince and
+// created at or after createdSince
+func FilterEnvVariablesSince(variables []models.EnvVariable, updatedSince, createdSince time.Time) []models.EnvVariable {
+	var result []models.EnvVariable
+	for _, v := range variables {
+		if !v.UpdatedAt.Before(updatedSince) && !v.CreatedAt.Before(createdSince) {
+			result = append(result, v)
 		}
+	}
 
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
+	return result
+}
 
-		// Remove quotes if present
-		if len(value) >= 2 && ((value[0] == '"' && value[len(value)-1] == '"') ||
-			(value[0] == '\'' && value[len(value)-1] == '\'')) {
-			value = value[1 : len(value)-1]
-		}
+// PageEnvVariables returns the page of variables starting at offset, with at most
+// limit variables (all remaining ones for a limit of 0)
+func PageEnvVariables(variables []models.EnvVariable, limit, offset int) []models.EnvVariable {
+	if offset >= len(variables) {
+		return nil
+	}
+	variables = variables[offset:]
+	if limit > 0 && limit < len(variables) {
+		variables = variables[:limit]
+	}
+	return variables
+}
 
-		// Save to database
-		_, err := h.repo.SetEnvVariable(project.ID, env.ID, key, value)
-		if err != nil {
-			return fmt.Errorf("failed to save env variable %s: %w", key, err)
-		}
+// ListDeletedEnvVariables lists the soft-deleted environment variables of a project environment
+func (h *EnvHandler) ListDeletedEnvVariables(projectName, environmentName string) ([]models.EnvVariable, error) {
+	// Check if project exists
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error reading env file: %w", err)
+	// Get environment
+	env, err := h.findEnvironment(project, environmentName)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	// Get deleted variables
+	variables, err := h.repo.GetDeletedEnvVariables(project.ID, env.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted environment variables: %w", err)
+	}
+
+	return variables, nil
 }
 
-// ExportEnvFile exports environment variables to a .env file
-func (h *EnvHandler) ExportEnvFile(filePath, projectName, environmentName string) error {
+// RestoreEnvVariable restores the most recently deleted version of an environment variable
+func (h *EnvHandler) RestoreEnvVariable(projectName, environmentName, key string) error {
 	// Check if project exists
-	project, err := h.repo.GetProjectByName(projectName)
+	project, err := h.findProject(projectName)
 	if err != nil {
-		return fmt.Errorf("project not found: %w", err)
+		return err
 	}
 
 	// Get environment
-	env, err := h.repo.GetEnvironmentByName(environmentName)
+	env, err := h.findEnvironment(project, environmentName)
 	if err != nil {
-		return fmt.Errorf("environment not found: %w", err)
+		return err
 	}
 
-	// Get all env variables for this project and environment
-	variables, err := h.repo.GetEnvVariables(project.ID, env.ID)
+	return h.repo.RestoreEnvVariable(project.ID, env.ID, key)
+}
+
+// CloneProject creates a new project with a copy of all variables of an existing one
+func (h *EnvHandler) CloneProject(sourceName, targetName string) error {
+	source, err := h.findProject(sourceName)
 	if err != nil {
-		return fmt.Errorf("failed to get environment variables: %w", err)
+		return err
 	}
 
-	// Create or truncate the file
-	file, err := os.Create(filePath)
+	_, err = h.repo.CloneProject(source.ID, targetName, fmt.Sprintf("Project cloned from %s", sourceName))
 	if err != nil {
-		return fmt.Errorf("failed to create env file: %w", err)
+		return fmt.Errorf("failed to clone project: %w", err)
 	}
-	defer file.Close()
 
-	// Write header
-	file.WriteString(fmt.Sprintf("# Environment variables for %s - %s\n", projectName, environmentName))
-	file.WriteString("# Generated by go-env-cli\n\n")
+	return nil
+}
 
-	// Write variables
-	for _, v := range variables {
-		file.WriteString(fmt.Sprintf("%s=%s\n", v.Key, v.Value))
+// CloneEnvironments gives the target project a copy of the environments of the source
+// project, with their descriptions and parents, and adds every key of the source to
+// the target with an empty value, as a placeholder to fill in. The target project is
+// created if it doesn't exist. It returns the number of placeholders added per
+// environment of the source.
+func (h *EnvHandler) CloneEnvironments(sourceName, targetName string) ([]models.VariableCount, error) {
+	source, err := h.findProject(sourceName)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
-}
+	target, err := h.repo.GetProjectByName(targetName)
+	if errors.Is(err, models.ErrProjectNotFound) {
+		target, err = h.repo.CreateProject(targetName, fmt.Sprintf("Project created from the environments of %s", sourceName))
+	}
+	if err != nil {
+		return nil, err
+	}
 
-// ListProjects lists all projects
-func (h *EnvHandler) ListProjects() ([]models.Project, error) {
-	return h.repo.GetAllProjects()
-}
+	seeded, err := h.repo.SeedPlaceholders(source.ID, target.ID)
+	if err != nil {
+		return nil, err
+	}
 
-// SearchProjects searches for projects by name pattern
-func (h *EnvHandler) SearchProjects(pattern string) ([]models.Project, error) {
-	return h.repo.SearchProjects(pattern)
+	targetEnvironments, err := h.repo.GetEnvironments(&target.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environments for project: %w", err)
+	}
+	names := make(map[uuid.UUID]string, len(targetEnvironments))
+	for _, e := range targetEnvironments {
+		names[e.ID] = strings.ToLower(e.Name)
+	}
+
+	perEnvironment := make(map[string]int)
+	for _, v := range seeded {
+		perEnvironment[names[v.EnvironmentID]]++
+	}
+
+	environments, err := h.repo.GetEnvironments(&source.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environments for project: %w", err)
+	}
+
+	counts := make([]models.VariableCount, 0, len(environments))
+	for _, e := range environments {
+		counts = append(counts, models.VariableCount{Name: e.Name, Count: perEnvironment[strings.ToLower(e.Name)]})
+	}
+
+	return counts, nil
 }
 
-// SetEnvVariable sets an environment variable
-func (h *EnvHandler) SetEnvVariable(projectName, environmentName, key, value string) error {
+// SoftDeleteProject soft-deletes a project
+func (h *EnvHandler) SoftDeleteProject(projectName string) error {
 	// Check if project exists
-	project, err := h.repo.GetProjectByName(projectName)
+	project, err := h.findProject(projectName)
 	if err != nil {
-		return fmt.Errorf("project not found: %w", err)
+		return err
 	}
 
-	// Get environment
-	env, err := h.repo.GetEnvironmentByName(environmentName)
+	// Soft delete the project
+	err = h.repo.SoftDeleteProject(project.ID)
 	if err != nil {
-		return fmt.Errorf("environment not found: %w", err)
+		return fmt.Errorf("failed to soft delete project: %w", err)
 	}
 
-	// Set the variable
-	_, err = h.repo.SetEnvVariable(project.ID, env.ID, key, value)
+	return nil
+}
+
+// UpdateProjectDescription updates the description of a project
+func (h *EnvHandler) UpdateProjectDescription(projectName, description string) error {
+	// Check if project exists
+	project, err := h.findProject(projectName)
 	if err != nil {
-		return fmt.Errorf("failed to set environment variable: %w", err)
+		return err
+	}
+
+	// Update the description
+	err = h.repo.UpdateProjectDescription(project.ID, description)
+	if err != nil {
+		return fmt.Errorf("failed to update project description: %w", err)
 	}
 
 	return nil
 }
 
-// GetEnvVariable gets an environment variable by key
-func (h *EnvHandler) GetEnvVariable(projectName, environmentName, key string) (string, error) {
-	// Check if project exists
-	project, err := h.repo.GetProjectByName(projectName)
+// ListEnvironments lists the environments of a project, or the shared environments
+// when projectName is empty
+func (h *EnvHandler) ListEnvironments(projectName string) ([]models.Environment, error) {
+	project, err := h.findScope(projectName)
 	if err != nil {
-		return "", fmt.Errorf("project not found: %w", err)
+		return nil, err
 	}
 
-	// Get environment
-	env, err := h.repo.GetEnvironmentByName(environmentName)
+	if project == nil {
+		return h.repo.GetEnvironments(nil)
+	}
+	return h.repo.GetEnvironments(&project.ID)
+}
+
+// CreateEnvironment creates a new environment in a project, or a shared environment
+// when projectName is empty
+func (h *EnvHandler) CreateEnvironment(projectName, name, description string) error {
+	project, err := h.findScope(projectName)
 	if err != nil {
-		return "", fmt.Errorf("environment not found: %w", err)
+		return err
 	}
 
-	// Get the variable
-	variable, err := h.repo.GetEnvVariable(project.ID, env.ID, key)
+	var projectID *uuid.UUID
+	if project != nil {
+		projectID = &project.ID
+	}
+
+	_, err = h.repo.CreateEnvironment(projectID, name, description)
 	if err != nil {
-		return "", fmt.Errorf("failed to get environment variable: %w", err)
+		return fmt.Errorf("failed to create environment: %w", err)
 	}
+	return nil
+}
 
-	return variable.Value, nil
+// EnsureEnvironment creates an environment in a project, or a shared environment when
+// projectName is empty, unless it already exists, and reports whether it was created.
+// A project that only has a shared environment of that name gets its own copy.
+func (h *EnvHandler) EnsureEnvironment(projectName, name, description string) (bool, error) {
+	project, err := h.findScope(projectName)
+	if err != nil {
+		return false, err
+	}
+
+	env, err := h.findEnvironment(project, name)
+	if err == nil && (project == nil || env.ProjectID != nil) {
+		return false, nil
+	}
+	if err != nil && !errors.Is(err, models.ErrEnvironmentNotFound) {
+		return false, err
+	}
+
+	if _, err := h.ensureEnvironment(project, name, description); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-// DeleteEnvVariable deletes an environment variable
-func (h *EnvHandler) DeleteEnvVariable(projectName, environmentName, key string) error {
-	// Check if project exists
-	project, err := h.repo.GetProjectByName(projectName)
+// UpdateEnvironmentDescription updates the description of an environment of a project,
+// or of a shared environment when projectName is empty
+func (h *EnvHandler) UpdateEnvironmentDescription(projectName, name, description string) error {
+	project, err := h.findScope(projectName)
 	if err != nil {
-		return fmt.Errorf("project not found: %w", err)
+		return err
 	}
 
 	// Get environment
-	env, err := h.repo.GetEnvironmentByName(environmentName)
+	env, err := h.writableEnvironment(project, name)
 	if err != nil {
-		return fmt.Errorf("environment not found: %w", err)
+		return err
 	}
 
-	// Delete the variable
-	err = h.repo.DeleteEnvVariable(project.ID, env.ID, key)
+	// Update the description
+	err = h.repo.UpdateEnvironmentDescription(env.ID, description)
 	if err != nil {
-		return fmt.Errorf("failed to delete environment variable: %w", err)
+		return fmt.Errorf("failed to update environment description: %w", err)
 	}
 
 	return nil
 }
 
-// ListEnvVariables lists all environment variables for a project and environment
-func (h *EnvHandler) ListEnvVariables(projectName, environmentName string) ([]models.EnvVariable, error) {
-	// Check if project exists
-	project, err := h.repo.GetProjectByName(projectName)
+// SetEnvironmentParent makes an environment inherit variables from a parent environment
+// of the same project, or between shared environments when projectName is empty. An
+// empty parentName removes the parent.
+func (h *EnvHandler) SetEnvironmentParent(projectName, name, parentName string) error {
+	project, err := h.findScope(projectName)
 	if err != nil {
-		return nil, fmt.Errorf("project not found: %w", err)
+		return err
 	}
 
 	// Get environment
-	env, err := h.repo.GetEnvironmentByName(environmentName)
+	env, err := h.writableEnvironment(project, name)
 	if err != nil {
-		return nil, fmt.Errorf("environment not found: %w", err)
+		return err
 	}
 
-	// Get variables
-	variables, err := h.repo.GetEnvVariables(project.ID, env.ID)
+	if parentName == "" {
+		if err := h.repo.SetEnvironmentParent(env.ID, nil); err != nil {
+			return fmt.Errorf("failed to remove environment parent: %w", err)
+		}
+		return nil
+	}
+
+	// Get parent environment
+	parent, err := h.writableEnvironment(project, parentName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list environment variables: %w", err)
+		return fmt.Errorf("parent %w", err)
 	}
 
-	return variables, nil
+	// Refuse parents that would make the inheritance chain loop back to this environment
+	ancestors, err := h.environmentChain(parent)
+	if err != nil {
+		return err
+	}
+	for _, ancestor := range ancestors {
+		if ancestor.ID == env.ID {
+			return fmt.Errorf("environment '%s' cannot inherit from '%s': inheritance would form a cycle", name, parentName)
+		}
+	}
+
+	if err := h.repo.SetEnvironmentParent(env.ID, &parent.ID); err != nil {
+		return fmt.Errorf("failed to set environment parent: %w", err)
+	}
+
+	return nil
 }
 
-// SoftDeleteProject soft-deletes a project
-func (h *EnvHandler) SoftDeleteProject(projectName string) error {
+// ListEnvVariablesInherited lists the variables of an environment merged on top of the
+// variables inherited from its parent environments. The environment's own variables
+// win over its parent's, which win over the grandparent's, and so on.
+func (h *EnvHandler) ListEnvVariablesInherited(projectName, environmentName string) ([]models.EnvVariable, error) {
 	// Check if project exists
-	project, err := h.repo.GetProjectByName(projectName)
+	project, err := h.findProject(projectName)
 	if err != nil {
-		return fmt.Errorf("project not found: %w", err)
+		return nil, err
 	}
 
-	// Soft delete the project
-	err = h.repo.SoftDeleteProject(project.ID)
+	// Get environment
+	env, err := h.findEnvironment(project, environmentName)
 	if err != nil {
-		return fmt.Errorf("failed to soft delete project: %w", err)
+		return nil, err
 	}
 
-	return nil
-}
+	chain, err := h.environmentChain(env)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load layers from the root ancestor down to the environment itself
+	layers := make([][]models.EnvVariable, 0, len(chain))
+	for i := len(chain) - 1; i >= 0; i-- {
+		variables, err := h.repo.GetEnvVariables(project.ID, chain[i].ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list environment variables: %w", err)
+		}
+		layers = append(layers, variables)
+	}
 
-// ListEnvironments lists all available environments
-func (h *EnvHandler) ListEnvironments() ([]models.Environment, error) {
-	return h.repo.GetAllEnvironments()
+	return MergeEnvVariables(layers...), nil
 }
 
-// CreateEnvironment creates a new environment
-func (h *EnvHandler) CreateEnvironment(name, description string) error {
-	_, err := h.repo.CreateEnvironment(name, description)
-	if err != nil {
-		return fmt.Errorf("failed to create environment: %w", err)
+// environmentChain returns env followed by its ancestors, nearest first
+func (h *EnvHandler) environmentChain(env *models.Environment) ([]models.Environment, error) {
+	chain := []models.Environment{*env}
+	seen := map[uuid.UUID]bool{env.ID: true}
+
+	for current := env; current.ParentID != nil; {
+		if seen[*current.ParentID] {
+			return nil, fmt.Errorf("environment inheritance cycle detected at '%s'", current.Name)
+		}
+
+		parent, err := h.repo.GetEnvironmentByID(*current.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent environment: %w", err)
+		}
+
+		seen[parent.ID] = true
+		chain = append(chain, *parent)
+		current = parent
 	}
-	return nil
+
+	return chain, nil
 }
 
 // SearchEnvVariables searches for environment variables by key pattern
@@ -278,7 +1278,11 @@ func (h *EnvHandler) SearchEnvVariables(projectName, environmentName, keyPattern
 		return nil, err
 	}
 
-	// Filter by key pattern
+	return FilterEnvVariables(variables, keyPattern), nil
+}
+
+// FilterEnvVariables returns the variables whose key contains keyPattern, ignoring case
+func FilterEnvVariables(variables []models.EnvVariable, keyPattern string) []models.EnvVariable {
 	var result []models.EnvVariable
 	pattern := strings.ToLower(keyPattern)
 	for _, v := range variables {
@@ -287,15 +1291,33 @@ func (h *EnvHandler) SearchEnvVariables(projectName, environmentName, keyPattern
 		}
 	}
 
-	return result, nil
+	return result
+}
+
+// FilterEnvVariablesRegex returns the variables whose key matches re
+func FilterEnvVariablesRegex(variables []models.EnvVariable, re *regexp.Regexp) []models.EnvVariable {
+	var result []models.EnvVariable
+	for _, v := range variables {
+		if re.MatchString(v.Key) {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+// SearchEnvVariablesGlobal searches for environment variables by key pattern across all projects and environments
+func (h *EnvHandler) SearchEnvVariablesGlobal(keyPattern string) ([]models.EnvVariableMatch, error) {
+	return h.repo.SearchEnvVariablesGlobal(keyPattern)
 }
 
-// GetEnvironmentsForProject gets all environments used by a specific project
+// GetEnvironmentsForProject gets all environments of a specific project, including those
+// without variables
 func (h *EnvHandler) GetEnvironmentsForProject(projectName string) ([]models.Environment, error) {
 	// Check if project exists
-	project, err := h.repo.GetProjectByName(projectName)
+	project, err := h.findProject(projectName)
 	if err != nil {
-		return nil, fmt.Errorf("project not found: %w", err)
+		return nil, err
 	}
 
 	// Get environments
@@ -307,8 +1329,46 @@ func (h *EnvHandler) GetEnvironmentsForProject(projectName string) ([]models.Env
 	return environments, nil
 }
 
-// createEnvBackup creates a backup of the .env file in the user's home directory
-func createEnvBackup(sourcePath, projectName string) error {
+// PurgeDeleted permanently removes projects and variables soft-deleted more than
+// olderThan ago. With dryRun set nothing is removed and the counts of what would be
+// removed are returned.
+func (h *EnvHandler) PurgeDeleted(olderThan time.Duration, dryRun bool) (*models.PurgeCounts, error) {
+	cutoff := time.Now().Add(-olderThan)
+	if dryRun {
+		return h.repo.CountPurgeable(cutoff)
+	}
+	return h.repo.PurgeDeleted(cutoff)
+}
+
+// Stats returns aggregate counts of projects, environments and variables
+func (h *EnvHandler) Stats() (*models.Stats, error) {
+	stats, err := h.repo.Stats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statistics: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetVariableCountsByEnvironment returns the number of variables in each environment of a project
+func (h *EnvHandler) GetVariableCountsByEnvironment(projectName string) ([]models.VariableCount, error) {
+	// Check if project exists
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get counts
+	counts, err := h.repo.GetVariableCountsByEnvironment(project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variable counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// createEnvBackup creates a backup of imported .env content in the user's home directory
+func createEnvBackup(content []byte, projectName string) error {
 	// Get user's home directory
 	usr, err := user.Current()
 	if err != nil {
@@ -330,25 +1390,53 @@ func createEnvBackup(sourcePath, projectName string) error {
 	backupFileName := fmt.Sprintf("backup-%s.txt", timestamp)
 	backupFilePath := filepath.Join(projectBackupDir, backupFileName)
 
-	// Open source file
-	srcFile, err := os.Open(sourcePath)
-	if err != nil {
-		return fmt.Errorf("failed to open source file: %w", err)
+	// Write contents
+	if err := os.WriteFile(backupFilePath, content, 0600); err != nil {
+		return fmt.Errorf("failed to write backup file: %w", err)
 	}
-	defer srcFile.Close()
 
-	// Create destination file
-	dstFile, err := os.Create(backupFilePath)
+	return nil
+}
+
+// writeFileAtomic writes a file readable only by its owner. Content is written to a
+// temporary file in the same directory which is then renamed over the target, so
+// readers never observe a partially written file.
+func writeFileAtomic(filePath string, write func(w io.Writer) error) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(filePath), "."+filepath.Base(filePath)+".tmp-*")
 	if err != nil {
-		return fmt.Errorf("failed to create backup file: %w", err)
+		return fmt.Errorf("failed to create temporary file: %w", err)
 	}
-	defer dstFile.Close()
+	tmpPath := tmpFile.Name()
 
-	// Copy contents
-	_, err = io.Copy(dstFile, srcFile)
-	if err != nil {
-		return fmt.Errorf("failed to copy file contents: %w", err)
+	// Clean up the temporary file unless it was renamed into place
+	renamed := false
+	defer func() {
+		if !renamed {
+			tmpFile.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if err := tmpFile.Chmod(0600); err != nil {
+		return fmt.Errorf("failed to set file permissions: %w", err)
+	}
+
+	if err := write(tmpFile); err != nil {
+		return fmt.Errorf("failed to write env file: %w", err)
+	}
+
+	if err := tmpFile.Sync(); err != nil {
+		return fmt.Errorf("failed to flush env file: %w", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close env file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		return fmt.Errorf("failed to move env file into place: %w", err)
 	}
+	renamed = true
 
 	return nil
 }
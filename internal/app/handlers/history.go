@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"fmt"
+
+	"go-env-cli/internal/app/models"
+)
+
+// LastChange gets the most recent set, delete or rename of a project environment that
+// hasn't been undone
+func (h *EnvHandler) LastChange(projectName, environmentName string) (*models.HistoryEntry, error) {
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := h.findEnvironment(project, environmentName)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.repo.GetLastHistoryEntry(project.ID, env.ID)
+}
+
+// UndoChange reverses a change returned by LastChange
+func (h *EnvHandler) UndoChange(entry *models.HistoryEntry) error {
+	return h.repo.UndoHistoryEntry(entry.ID)
+}
+
+// DescribeUndo describes what undoing a change does, such as "restore API_KEY to its
+// previous value"
+func DescribeUndo(entry *models.HistoryEntry) string {
+	switch {
+	case entry.Operation == models.HistoryRename:
+		return fmt.Sprintf("rename %s back to %s", entry.Key, entry.OldKey)
+	case entry.Operation == models.HistorySet && entry.OldValue == nil:
+		return fmt.Sprintf("delete %s, which was created", entry.Key)
+	case entry.Operation == models.HistorySet:
+		return fmt.Sprintf("restore the previous value of %s", entry.Key)
+	default:
+		return fmt.Sprintf("restore %s, which was deleted", entry.Key)
+	}
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+
+	"go-env-cli/internal/app/models"
+	"go-env-cli/internal/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// findProject gets an active project by name. A missing project is reported as
+// models.ErrProjectNotFound, suggesting the closest existing project name, if any.
+func (h *EnvHandler) findProject(name string) (*models.Project, error) {
+	project, err := h.repo.GetProjectByName(name)
+	if err == nil {
+		return project, nil
+	}
+
+	if errors.Is(err, models.ErrProjectNotFound) {
+		if projects, listErr := h.repo.GetAllProjects(); listErr == nil {
+			names := make([]string, 0, len(projects))
+			for _, p := range projects {
+				names = append(names, p.Name)
+			}
+			if match, ok := utils.ClosestMatch(name, names); ok {
+				return nil, fmt.Errorf("%w (did you mean '%s'?)", err, match)
+			}
+		}
+	}
+
+	return nil, err
+}
+
+// findScope gets the project environments are looked up in. An empty projectName selects
+// the shared environments, for which a nil project is returned.
+func (h *EnvHandler) findScope(projectName string) (*models.Project, error) {
+	if projectName == "" {
+		return nil, nil
+	}
+	return h.findProject(projectName)
+}
+
+// findEnvironment gets an environment of a project by name, or a shared environment when
+// project is nil. A project that doesn't have the environment yet gets the shared
+// environment of that name, if there is one, which holds none of its variables; nothing
+// is created, so reads leave the database untouched. A missing environment is reported
+// as models.ErrEnvironmentNotFound, suggesting the closest existing environment name, if
+// any.
+func (h *EnvHandler) findEnvironment(project *models.Project, name string) (*models.Environment, error) {
+	var projectID *uuid.UUID
+	if project != nil {
+		projectID = &project.ID
+	}
+
+	env, err := h.repo.GetEnvironmentByName(projectID, name)
+	if err == nil {
+		return env, nil
+	}
+
+	if errors.Is(err, models.ErrEnvironmentNotFound) && project != nil {
+		shared, sharedErr := h.repo.GetEnvironmentByName(nil, name)
+		if sharedErr == nil {
+			return shared, nil
+		}
+		if !errors.Is(sharedErr, models.ErrEnvironmentNotFound) {
+			return nil, sharedErr
+		}
+	}
+
+	if errors.Is(err, models.ErrEnvironmentNotFound) {
+		scopes := []*uuid.UUID{nil}
+		if projectID != nil {
+			scopes = append(scopes, projectID)
+		}
+
+		var names []string
+		for _, id := range scopes {
+			if environments, listErr := h.repo.GetEnvironments(id); listErr == nil {
+				for _, e := range environments {
+					names = append(names, e.Name)
+				}
+			}
+		}
+		if match, ok := utils.ClosestMatch(name, names); ok {
+			return nil, fmt.Errorf("%w (did you mean '%s'?)", err, match)
+		}
+	}
+
+	return nil, err
+}
+
+// writableEnvironment gets an environment of a project by name like findEnvironment, for
+// commands about to change it. A project that doesn't have the environment yet gets its
+// own copy of the shared environment of that name, so the change doesn't touch the
+// shared template.
+func (h *EnvHandler) writableEnvironment(project *models.Project, name string) (*models.Environment, error) {
+	env, err := h.findEnvironment(project, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if project != nil && env.ProjectID == nil {
+		if env, err = h.repo.AdoptEnvironment(project.ID, env); err != nil {
+			return nil, fmt.Errorf("failed to adopt environment: %w", err)
+		}
+	}
+
+	return env, nil
+}
+
+// ensureEnvironment gets an environment of a project by name like writableEnvironment,
+// creating it with description when neither the project nor the shared environments
+// have it
+func (h *EnvHandler) ensureEnvironment(project *models.Project, name, description string) (*models.Environment, error) {
+	env, err := h.writableEnvironment(project, name)
+	if !errors.Is(err, models.ErrEnvironmentNotFound) {
+		return env, err
+	}
+
+	var projectID *uuid.UUID
+	if project != nil {
+		projectID = &project.ID
+	}
+
+	env, err = h.repo.CreateEnvironment(projectID, name, description)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create environment: %w", err)
+	}
+
+	return env, nil
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"sort"
+
+	"go-env-cli/internal/app/models"
+)
+
+// MergeEnvVariables overlays layers of environment variables from left to right.
+// When a key appears in more than one layer the variable from the last layer wins.
+// The result is sorted by key.
+func MergeEnvVariables(layers ...[]models.EnvVariable) []models.EnvVariable {
+	merged := make(map[string]models.EnvVariable)
+	for _, layer := range layers {
+		for _, v := range layer {
+			merged[v.Key] = v
+		}
+	}
+
+	result := make([]models.EnvVariable, 0, len(merged))
+	for _, v := range merged {
+		result = append(result, v)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+
+	return result
+}
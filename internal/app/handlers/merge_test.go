@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"go-env-cli/internal/app/models"
+
+	"github.com/google/uuid"
+)
+
+func TestMergeEnvVariables(t *testing.T) {
+	base := []models.EnvVariable{{Key: "HOST", Value: "localhost"}, {Key: "PORT", Value: "80"}, {Key: "SHARED", Value: "base"}}
+	staging := []models.EnvVariable{{Key: "HOST", Value: "staging"}, {Key: "DEBUG", Value: "true"}}
+	prod := []models.EnvVariable{{Key: "HOST", Value: "prod"}, {Key: "PORT", Value: "443"}}
+
+	tests := []struct {
+		name   string
+		layers [][]models.EnvVariable
+		want   map[string]string
+	}{
+		{"none", nil, map[string]string{}},
+		{"one", [][]models.EnvVariable{base}, map[string]string{"HOST": "localhost", "PORT": "80", "SHARED": "base"}},
+		{
+			"child wins",
+			[][]models.EnvVariable{base, prod},
+			map[string]string{"HOST": "prod", "PORT": "443", "SHARED": "base"},
+		},
+		{
+			"last of three wins",
+			[][]models.EnvVariable{base, staging, prod},
+			map[string]string{"HOST": "prod", "PORT": "443", "SHARED": "base", "DEBUG": "true"},
+		},
+		{
+			"order matters",
+			[][]models.EnvVariable{prod, base},
+			map[string]string{"HOST": "localhost", "PORT": "80", "SHARED": "base"},
+		},
+		{"empty child", [][]models.EnvVariable{base, nil}, map[string]string{"HOST": "localhost", "PORT": "80", "SHARED": "base"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := MergeEnvVariables(tt.layers...)
+
+			got := make(map[string]string, len(merged))
+			for i, v := range merged {
+				got[v.Key] = v.Value
+				if i > 0 && merged[i-1].Key >= v.Key {
+					t.Errorf("keys not sorted: %q before %q", merged[i-1].Key, v.Key)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeEnvVariables() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeEnvVariablesDeterministic(t *testing.T) {
+	base, staging, prod := uuid.New(), uuid.New(), uuid.New()
+	layers := [][]models.EnvVariable{
+		{{Key: "A", Value: "base", EnvironmentID: base}, {Key: "B", Value: "base", EnvironmentID: base}, {Key: "C", Value: "base", EnvironmentID: base}},
+		{{Key: "B", Value: "staging", EnvironmentID: staging}, {Key: "D", Value: "staging", EnvironmentID: staging}},
+		{{Key: "C", Value: "prod", EnvironmentID: prod}, {Key: "D", Value: "prod", EnvironmentID: prod}},
+	}
+	want := []models.EnvVariable{
+		{Key: "A", Value: "base", EnvironmentID: base},
+		{Key: "B", Value: "staging", EnvironmentID: staging},
+		{Key: "C", Value: "prod", EnvironmentID: prod},
+		{Key: "D", Value: "prod", EnvironmentID: prod},
+	}
+
+	// The merge goes through a map, so repeat it to catch any dependence on its order
+	var first []byte
+	for i := 0; i < 50; i++ {
+		merged := MergeEnvVariables(layers...)
+		if !reflect.DeepEqual(merged, want) {
+			t.Fatalf("MergeEnvVariables() = %+v, want %+v", merged, want)
+		}
+
+		var buf bytes.Buffer
+		if err := renderVariables(&buf, "project", "prod", merged, ExportOptions{}); err != nil {
+			t.Fatalf("renderVariables() error = %v", err)
+		}
+		if i == 0 {
+			first = buf.Bytes()
+		} else if !bytes.Equal(buf.Bytes(), first) {
+			t.Fatalf("export %d = %q, want %q", i, buf.Bytes(), first)
+		}
+	}
+}
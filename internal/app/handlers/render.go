@@ -0,0 +1,688 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go-env-cli/internal/app/models"
+	"go-env-cli/internal/pkg/utils"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Export formats supported by ExportOptions.Format
+const (
+	FormatDotenv     = "dotenv"
+	FormatJSON       = "json"
+	FormatK8sSecret  = "k8s-secret"
+	FormatEnvrc      = "envrc"
+	FormatSystemd    = "systemd"
+	FormatPowerShell = "powershell"
+	FormatFish       = "fish"
+	FormatCSV        = "csv"
+	FormatTOML       = "toml"
+	FormatSSM        = "ssm"
+	FormatMarkdown   = "markdown"
+	FormatTfvars     = "tfvars"
+)
+
+// ExportFormats lists every supported export format
+var ExportFormats = []string{FormatDotenv, FormatJSON, FormatK8sSecret, FormatEnvrc, FormatSystemd, FormatPowerShell, FormatFish, FormatCSV, FormatTOML, FormatSSM, FormatMarkdown, FormatTfvars}
+
+// Export orders supported by ExportOptions.Order
+const (
+	OrderKey      = "key"
+	OrderCreated  = "created"
+	OrderOriginal = "original"
+)
+
+// ExportOrders lists every supported export order
+var ExportOrders = []string{OrderKey, OrderCreated, OrderOriginal}
+
+// ImportFormats lists every supported import format
+var ImportFormats = []string{FormatDotenv, FormatCSV}
+
+// k8sSecretKeyPattern matches keys allowed in the data of a Kubernetes Secret
+var k8sSecretKeyPattern = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// ExportOptions controls how variables are rendered by ExportEnvFile
+type ExportOptions struct {
+	// Format selects the output format, defaulting to FormatDotenv when empty
+	Format string
+
+	// WithExport prefixes every assignment with "export " so the file can be sourced by a shell
+	WithExport bool
+
+	// Example replaces every value with Placeholder, producing a template such as
+	// .env.example that can be committed without leaking secrets
+	Example     bool
+	Placeholder string
+
+	// SecretName and Namespace set the metadata of a FormatK8sSecret manifest.
+	// SecretName defaults to "<project>-<environment>".
+	SecretName string
+	Namespace  string
+
+	// Prefix limits the export to keys starting with it. StripPrefix removes it from
+	// the exported keys.
+	Prefix      string
+	StripPrefix bool
+
+	// PathPrefix is the parameter hierarchy of a FormatSSM export, defaulting to
+	// "/<project>/<environment>"
+	PathPrefix string
+
+	// NoValues leaves the values out of a FormatMarkdown table
+	NoValues bool
+
+	// Overlays are environments merged over the exported one from left to right, so
+	// a key in a later environment wins. The output is then named after the last one.
+	Overlays []string
+
+	// Keys limits the export to the named keys. A missing key fails the export unless
+	// IgnoreMissing is set.
+	Keys          []string
+	IgnoreMissing bool
+
+	// Exclude drops the keys matching any of these glob patterns, after Keys is applied
+	Exclude []string
+
+	// EncryptTo encrypts the output to this recipient with age (for age1... and ssh-
+	// keys) or gpg (for anything else), see utils.Encrypt
+	EncryptTo string
+
+	// Order sets the order of the exported variables, defaulting to OrderKey when
+	// empty. See SortEnvVariables.
+	Order string
+}
+
+// ValidateExportFormat returns an error if format is not a supported export format
+func ValidateExportFormat(format string) error {
+	if format == "" {
+		return nil
+	}
+	for _, f := range ExportFormats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported format '%s' (supported: %s)", format, strings.Join(ExportFormats, ", "))
+}
+
+// ValidateExportOrder returns an error if order is not a supported export order
+func ValidateExportOrder(order string) error {
+	if order == "" {
+		return nil
+	}
+	for _, o := range ExportOrders {
+		if o == order {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported order '%s' (supported: %s)", order, strings.Join(ExportOrders, ", "))
+}
+
+// SortEnvVariables returns a copy of variables in the given order: OrderKey sorts by
+// key, OrderCreated by creation time and OrderOriginal by the position of each
+// variable in the file it was last imported from. Variables without a position, such
+// as those set one at a time, follow the imported ones sorted by key.
+func SortEnvVariables(variables []models.EnvVariable, order string) []models.EnvVariable {
+	sorted := make([]models.EnvVariable, len(variables))
+	copy(sorted, variables)
+
+	switch order {
+	case OrderCreated:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			if !sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+				return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+			}
+			return sorted[i].Key < sorted[j].Key
+		})
+	case OrderOriginal:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			a, b := sorted[i].Position, sorted[j].Position
+			switch {
+			case a != nil && b != nil && *a != *b:
+				return *a < *b
+			case a != nil && b == nil:
+				return true
+			case a == nil && b != nil:
+				return false
+			}
+			return sorted[i].Key < sorted[j].Key
+		})
+	default:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Key < sorted[j].Key
+		})
+	}
+
+	return sorted
+}
+
+// ValidateImportFormat returns an error if format is not a supported import format
+func ValidateImportFormat(format string) error {
+	if format == "" {
+		return nil
+	}
+	for _, f := range ImportFormats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported import format '%s' (supported: %s)", format, strings.Join(ImportFormats, ", "))
+}
+
+// FormatExtension returns the file extension conventionally used for a format
+func FormatExtension(format string) string {
+	switch format {
+	case FormatJSON:
+		return ".json"
+	case FormatK8sSecret:
+		return ".yaml"
+	case FormatEnvrc:
+		return ".envrc"
+	case FormatPowerShell:
+		return ".ps1"
+	case FormatFish:
+		return ".fish"
+	case FormatCSV:
+		return ".csv"
+	case FormatTOML:
+		return ".toml"
+	case FormatSSM:
+		return ".sh"
+	case FormatMarkdown:
+		return ".md"
+	case FormatTfvars:
+		return ".tfvars"
+	}
+	return ".env"
+}
+
+// renderVariables writes variables in the format selected by opts
+func renderVariables(w io.Writer, projectName, environmentName string, variables []models.EnvVariable, opts ExportOptions) error {
+	if opts.Prefix != "" {
+		variables = FilterPrefix(variables, opts.Prefix, opts.StripPrefix)
+	}
+	if opts.Example {
+		variables = blankValues(variables, opts.Placeholder)
+	}
+	if opts.Order != "" && opts.Order != OrderKey {
+		variables = SortEnvVariables(variables, opts.Order)
+	}
+
+	switch opts.Format {
+	case "", FormatDotenv:
+		return renderDotenv(w, projectName, environmentName, variables, opts)
+	case FormatJSON:
+		// JSON objects used to be written from a map, always sorted by key
+		return renderJSON(w, SortEnvVariables(variables, opts.Order))
+	case FormatK8sSecret:
+		return renderK8sSecret(w, projectName, environmentName, variables, opts)
+	case FormatEnvrc:
+		return renderEnvrc(w, projectName, environmentName, variables)
+	case FormatSystemd:
+		return renderSystemd(w, projectName, environmentName, variables)
+	case FormatPowerShell:
+		return renderPowerShell(w, projectName, environmentName, variables)
+	case FormatFish:
+		return renderFish(w, projectName, environmentName, variables)
+	case FormatCSV:
+		return renderCSV(w, variables)
+	case FormatTOML:
+		return renderTOML(w, projectName, environmentName, variables)
+	case FormatSSM:
+		return renderSSM(w, projectName, environmentName, variables, opts)
+	case FormatMarkdown:
+		return renderMarkdown(w, projectName, environmentName, variables, opts)
+	case FormatTfvars:
+		return renderTfvars(w, projectName, environmentName, variables)
+	}
+	return ValidateExportFormat(opts.Format)
+}
+
+// blankValues returns a copy of variables with every value replaced by placeholder
+func blankValues(variables []models.EnvVariable, placeholder string) []models.EnvVariable {
+	blanked := make([]models.EnvVariable, len(variables))
+	for i, v := range variables {
+		v.Value = placeholder
+		blanked[i] = v
+	}
+	return blanked
+}
+
+// FilterPrefix returns the variables whose key starts with prefix. When strip is true
+// the prefix is removed from the returned keys, and a variable named exactly prefix is
+// dropped since it would be left without a key.
+func FilterPrefix(variables []models.EnvVariable, prefix string, strip bool) []models.EnvVariable {
+	filtered := []models.EnvVariable{}
+	for _, v := range variables {
+		if !strings.HasPrefix(v.Key, prefix) {
+			continue
+		}
+		if strip {
+			v.Key = strings.TrimPrefix(v.Key, prefix)
+			if v.Key == "" {
+				continue
+			}
+		}
+		filtered = append(filtered, v)
+	}
+	return filtered
+}
+
+// SelectKeys returns the variables whose key is one of keys. A key that no variable has
+// is reported as models.ErrVariableNotFound, unless ignoreMissing is true.
+func SelectKeys(variables []models.EnvVariable, keys []string, ignoreMissing bool) ([]models.EnvVariable, error) {
+	wanted := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		wanted[key] = true
+	}
+
+	selected := []models.EnvVariable{}
+	found := make(map[string]bool, len(keys))
+	for _, v := range variables {
+		if wanted[v.Key] {
+			selected = append(selected, v)
+			found[v.Key] = true
+		}
+	}
+
+	if !ignoreMissing {
+		var missing []string
+		for _, key := range keys {
+			if !found[key] {
+				missing = append(missing, key)
+				found[key] = true
+			}
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("%w: %s", models.ErrVariableNotFound, strings.Join(missing, ", "))
+		}
+	}
+
+	return selected, nil
+}
+
+// ExcludeKeys returns the variables whose key matches none of the glob patterns,
+// ignoring case
+func ExcludeKeys(variables []models.EnvVariable, patterns []string) []models.EnvVariable {
+	globs := utils.CompileGlobs(patterns)
+	kept := []models.EnvVariable{}
+	for _, v := range variables {
+		if !globs.MatchAny(v.Key) {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// renderDotenv writes variables in .env format
+func renderDotenv(w io.Writer, projectName, environmentName string, variables []models.EnvVariable, opts ExportOptions) error {
+	// Write header
+	if _, err := fmt.Fprintf(w, "# Environment variables for %s - %s\n", projectName, environmentName); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, "# Generated by go-env-cli\n\n"); err != nil {
+		return err
+	}
+
+	// Write variables
+	prefix := ""
+	if opts.WithExport {
+		prefix = "export "
+	}
+	for _, v := range variables {
+		if _, err := fmt.Fprintf(w, "%s%s=%s\n", prefix, v.Key, utils.FormatEnvValue(v.Value)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderEnvrc writes variables as shell-quoted export statements for direnv's .envrc
+func renderEnvrc(w io.Writer, projectName, environmentName string, variables []models.EnvVariable) error {
+	if err := validateShellKeys(variables); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "# Environment variables for %s - %s\n# Generated by go-env-cli\n\n", projectName, environmentName); err != nil {
+		return err
+	}
+
+	for _, v := range variables {
+		if _, err := fmt.Fprintf(w, "export %s=%s\n", v.Key, utils.QuoteShell(v.Value)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderSystemd writes variables for a systemd EnvironmentFile=, one KEY=value per
+// line. Values are written unquoted unless systemd would read them back differently,
+// see utils.QuoteSystemd. systemd cannot represent values containing line breaks, so
+// such values are rejected.
+func renderSystemd(w io.Writer, projectName, environmentName string, variables []models.EnvVariable) error {
+	if err := validateShellKeys(variables); err != nil {
+		return err
+	}
+
+	var invalid []string
+	for _, v := range variables {
+		if strings.ContainsAny(v.Value, "\n\r") {
+			invalid = append(invalid, v.Key)
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("values with line breaks can't be written to a systemd EnvironmentFile: %s",
+			strings.Join(invalid, ", "))
+	}
+
+	if _, err := fmt.Fprintf(w, "# Environment variables for %s - %s\n# Generated by go-env-cli\n\n", projectName, environmentName); err != nil {
+		return err
+	}
+
+	for _, v := range variables {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", v.Key, utils.QuoteSystemd(v.Value)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderPowerShell writes variables as PowerShell $env: assignments
+func renderPowerShell(w io.Writer, projectName, environmentName string, variables []models.EnvVariable) error {
+	if err := validateShellKeys(variables); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "# Environment variables for %s - %s\n# Generated by go-env-cli\n\n", projectName, environmentName); err != nil {
+		return err
+	}
+
+	for _, v := range variables {
+		if _, err := fmt.Fprintf(w, "$env:%s = %s\n", v.Key, utils.QuotePowerShell(v.Value)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderFish writes variables as fish exported set commands
+func renderFish(w io.Writer, projectName, environmentName string, variables []models.EnvVariable) error {
+	if err := validateShellKeys(variables); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "# Environment variables for %s - %s\n# Generated by go-env-cli\n\n", projectName, environmentName); err != nil {
+		return err
+	}
+
+	for _, v := range variables {
+		if _, err := fmt.Fprintf(w, "set -gx %s %s\n", v.Key, utils.QuoteFish(v.Value)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateShellKeys returns an error listing every key that is not a valid shell variable name
+func validateShellKeys(variables []models.EnvVariable) error {
+	var invalid []string
+	for _, v := range variables {
+		if !utils.IsShellIdentifier(v.Key) {
+			invalid = append(invalid, v.Key)
+		}
+	}
+
+	if len(invalid) > 0 {
+		return fmt.Errorf("keys not valid as shell variable names: %s", strings.Join(invalid, ", "))
+	}
+
+	return nil
+}
+
+// renderJSON writes variables as a JSON object of key/value pairs
+func renderJSON(w io.Writer, variables []models.EnvVariable) error {
+	// Write the object by hand rather than from a map, so the keys keep the order
+	// of variables. A key repeated after --strip-prefix keeps its last value.
+	var keys []string
+	values := make(map[string]string, len(variables))
+	for _, v := range variables {
+		if _, ok := values[v.Key]; !ok {
+			keys = append(keys, v.Key)
+		}
+		values[v.Key] = v.Value
+	}
+
+	if len(keys) == 0 {
+		_, err := io.WriteString(w, "{}\n")
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	for i, key := range keys {
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return err
+		}
+		encodedValue, err := json.Marshal(values[key])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "  %s: %s", encodedKey, encodedValue)
+		if i < len(keys)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// renderCSV writes variables as CSV with a key,value header, quoting values as
+// described in RFC 4180
+func renderCSV(w io.Writer, variables []models.EnvVariable) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"key", "value"}); err != nil {
+		return err
+	}
+	for _, v := range variables {
+		if err := writer.Write([]string{v.Key, v.Value}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// renderTOML writes variables as key = "value" pairs of a flat [env] table
+func renderTOML(w io.Writer, projectName, environmentName string, variables []models.EnvVariable) error {
+	if _, err := fmt.Fprintf(w, "# Environment variables for %s - %s\n# Generated by go-env-cli\n\n[env]\n", projectName, environmentName); err != nil {
+		return err
+	}
+
+	for _, v := range variables {
+		if _, err := fmt.Fprintf(w, "%s = %s\n", utils.QuoteTOMLKey(v.Key), utils.QuoteTOML(v.Value)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderSSM writes an "aws ssm put-parameter" command storing each variable as a
+// SecureString parameter named <path prefix>/<key>. Nothing is sent to AWS.
+func renderSSM(w io.Writer, projectName, environmentName string, variables []models.EnvVariable, opts ExportOptions) error {
+	pathPrefix := opts.PathPrefix
+	if pathPrefix == "" {
+		pathPrefix = "/" + projectName + "/" + environmentName
+	}
+	pathPrefix = strings.TrimRight(pathPrefix, "/")
+
+	if _, err := fmt.Fprintf(w, "# AWS SSM parameters for %s - %s\n# Generated by go-env-cli\n\n", projectName, environmentName); err != nil {
+		return err
+	}
+
+	for _, v := range variables {
+		_, err := fmt.Fprintf(w, "aws ssm put-parameter --name %s --value %s --type SecureString --overwrite\n",
+			utils.QuoteShell(pathPrefix+"/"+v.Key), utils.QuoteShell(v.Value))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderTfvars writes variables as Terraform key = "value" assignments for a .tfvars
+// file. Keys that aren't valid Terraform variable names are rejected.
+func renderTfvars(w io.Writer, projectName, environmentName string, variables []models.EnvVariable) error {
+	var invalid []string
+	for _, v := range variables {
+		if !utils.IsTerraformVariableName(v.Key) {
+			invalid = append(invalid, v.Key)
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("keys not valid as Terraform variable names (letters, digits, _ and -, not starting with a digit or -, and not reserved): %s",
+			strings.Join(invalid, ", "))
+	}
+
+	if _, err := fmt.Fprintf(w, "# Environment variables for %s - %s\n# Generated by go-env-cli\n\n", projectName, environmentName); err != nil {
+		return err
+	}
+
+	for _, v := range variables {
+		if _, err := fmt.Fprintf(w, "%s = %s\n", v.Key, utils.QuoteHCL(v.Value)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// markdownTimestampFormat is the format of the update times in a FormatMarkdown table
+const markdownTimestampFormat = "2006-01-02 15:04:05"
+
+// renderMarkdown writes variables as a Markdown table with a section for the
+// environment, for pasting into a runbook or wiki
+func renderMarkdown(w io.Writer, projectName, environmentName string, variables []models.EnvVariable, opts ExportOptions) error {
+	if _, err := fmt.Fprintf(w, "# %s\n\n## %s\n\n", escapeMarkdown(projectName), escapeMarkdown(environmentName)); err != nil {
+		return err
+	}
+
+	if len(variables) == 0 {
+		_, err := io.WriteString(w, "No variables.\n")
+		return err
+	}
+
+	header := "| Key | Value | Updated |\n|-----|-------|---------|\n"
+	if opts.NoValues {
+		header = "| Key | Updated |\n|-----|---------|\n"
+	}
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+
+	for _, v := range variables {
+		updated := v.UpdatedAt.Format(markdownTimestampFormat)
+		var err error
+		if opts.NoValues {
+			_, err = fmt.Fprintf(w, "| %s | %s |\n", escapeMarkdown(v.Key), updated)
+		} else {
+			_, err = fmt.Fprintf(w, "| %s | %s | %s |\n", escapeMarkdown(v.Key), escapeMarkdown(v.Value), updated)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// escapeMarkdown escapes text for a Markdown table cell: pipes and Markdown
+// punctuation are backslash-escaped and line breaks become <br>
+func escapeMarkdown(text string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		"&", "&amp;",
+		"|", `\|`,
+		"*", `\*`,
+		"_", `\_`,
+		"`", "\\`",
+		"<", "&lt;",
+		"\r\n", "<br>",
+		"\n", "<br>",
+		"\r", "<br>",
+	)
+	return replacer.Replace(text)
+}
+
+// k8sSecret is a Kubernetes Secret manifest
+type k8sSecret struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   k8sMetadata       `yaml:"metadata"`
+	Type       string            `yaml:"type"`
+	Data       map[string]string `yaml:"data"`
+}
+
+// k8sMetadata is the metadata of a Kubernetes object
+type k8sMetadata struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+// renderK8sSecret writes variables as an Opaque Kubernetes Secret with base64 encoded values
+func renderK8sSecret(w io.Writer, projectName, environmentName string, variables []models.EnvVariable, opts ExportOptions) error {
+	name := opts.SecretName
+	if name == "" {
+		name = projectName + "-" + environmentName
+	}
+
+	secret := k8sSecret{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Metadata:   k8sMetadata{Name: name, Namespace: opts.Namespace},
+		Type:       "Opaque",
+		Data:       make(map[string]string, len(variables)),
+	}
+
+	var invalid []string
+	for _, v := range variables {
+		if !k8sSecretKeyPattern.MatchString(v.Key) {
+			invalid = append(invalid, v.Key)
+			continue
+		}
+		secret.Data[v.Key] = base64.StdEncoding.EncodeToString([]byte(v.Value))
+	}
+
+	if len(invalid) > 0 {
+		return fmt.Errorf("keys not valid in a Kubernetes Secret: %s", strings.Join(invalid, ", "))
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(secret); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"fmt"
+
+	"go-env-cli/internal/app/models"
+	"go-env-cli/internal/pkg/utils"
+)
+
+// Replacement is one change planned by PlanReplace: a value rewritten in place, or a
+// key renamed to NewKey with its value unchanged
+type Replacement struct {
+	Key      string
+	NewKey   string
+	OldValue string
+	NewValue string
+}
+
+// PlanReplace works out the changes replacing the matches of replacer in the values of
+// an environment would make, or in its keys when keys is true, without changing
+// anything. The changes are ordered by key. Renames fail when a new key isn't a valid
+// key, or would be used by two variables once every rename is done; keys renamed to
+// each other, such as A to B and B to A, are fine.
+func (h *EnvHandler) PlanReplace(projectName, environmentName string, replacer *utils.Replacer, keys bool) ([]Replacement, error) {
+	variables, err := h.ListEnvVariables(projectName, environmentName)
+	if err != nil {
+		return nil, err
+	}
+
+	var replacements []Replacement
+	for _, v := range variables {
+		if !keys {
+			if value, changed := replacer.Replace(v.Value); changed {
+				replacements = append(replacements, Replacement{Key: v.Key, OldValue: v.Value, NewValue: value})
+			}
+			continue
+		}
+
+		newKey, changed := replacer.Replace(v.Key)
+		if !changed {
+			continue
+		}
+		if err := utils.ValidateKey(newKey); err != nil {
+			return nil, fmt.Errorf("cannot rename '%s': %w", v.Key, err)
+		}
+
+		replacements = append(replacements, Replacement{Key: v.Key, NewKey: newKey, OldValue: v.Value, NewValue: v.Value})
+	}
+
+	if err := checkRenames(variables, replacements); err != nil {
+		return nil, err
+	}
+
+	return replacements, nil
+}
+
+// checkRenames returns an error if the renames among replacements would leave two
+// variables with the same key, comparing the keys as they are after every rename
+func checkRenames(variables []models.EnvVariable, replacements []Replacement) error {
+	renamed := make(map[string]bool, len(replacements))
+	for _, r := range replacements {
+		if r.NewKey != "" {
+			renamed[r.Key] = true
+		}
+	}
+
+	// Keys that stay as they are, then the new ones
+	final := make(map[string]string, len(variables))
+	for _, v := range variables {
+		if !renamed[v.Key] {
+			final[v.Key] = v.Key
+		}
+	}
+	for _, r := range replacements {
+		if r.NewKey == "" {
+			continue
+		}
+		if other, ok := final[r.NewKey]; ok {
+			if other == r.NewKey {
+				return fmt.Errorf("cannot rename '%s': an environment variable with key '%s' %w", r.Key, r.NewKey, models.ErrAlreadyExists)
+			}
+			return fmt.Errorf("cannot rename both '%s' and '%s' to '%s': keys %w", other, r.Key, r.NewKey, models.ErrAlreadyExists)
+		}
+		final[r.NewKey] = r.Key
+	}
+
+	return nil
+}
+
+// ApplyReplacements applies the changes planned by PlanReplace to an environment in a
+// single transaction, after checking the new values against their declared types.
+// Nothing is changed when one of them fails.
+func (h *EnvHandler) ApplyReplacements(projectName, environmentName string, replacements []Replacement) error {
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	env, err := h.findEnvironment(project, environmentName)
+	if err != nil {
+		return err
+	}
+
+	// A renamed variable keeps its value, which must suit the type of its new key
+	values := make(map[string]string, len(replacements))
+	renames := make(map[string]string)
+	for _, r := range replacements {
+		if r.NewKey != "" {
+			renames[r.Key] = r.NewKey
+			values[r.NewKey] = r.NewValue
+		} else {
+			values[r.Key] = r.NewValue
+		}
+	}
+
+	if err := h.validateValues(project.ID, values); err != nil {
+		return err
+	}
+
+	if len(renames) > 0 {
+		err = h.repo.RenameEnvVariables(project.ID, env.ID, renames)
+	} else {
+		err = h.repo.ApplyEnvChanges(project.ID, env.ID, values, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply replacements: %w", err)
+	}
+
+	return nil
+}
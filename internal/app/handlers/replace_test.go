@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+
+	"go-env-cli/internal/app/models"
+)
+
+func TestCheckRenames(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    []string
+		renames map[string]string
+		wantErr bool
+	}{
+		{"rename", []string{"A", "B"}, map[string]string{"A": "C"}, false},
+		{"chain", []string{"A", "B"}, map[string]string{"A": "B", "B": "C"}, false},
+		{"swap", []string{"A", "B"}, map[string]string{"A": "B", "B": "A"}, false},
+		{"existing key", []string{"A", "B"}, map[string]string{"A": "B"}, true},
+		{"same target", []string{"A", "B"}, map[string]string{"A": "C", "B": "C"}, true},
+		{"chain onto a kept key", []string{"A", "B", "C"}, map[string]string{"A": "B", "B": "C"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var variables []models.EnvVariable
+			var replacements []Replacement
+			for _, key := range tt.keys {
+				variables = append(variables, models.EnvVariable{Key: key})
+				if newKey, ok := tt.renames[key]; ok {
+					replacements = append(replacements, Replacement{Key: key, NewKey: newKey})
+				}
+			}
+
+			err := checkRenames(variables, replacements)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkRenames() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, models.ErrAlreadyExists) {
+				t.Errorf("checkRenames() error = %v, want ErrAlreadyExists", err)
+			}
+		})
+	}
+}
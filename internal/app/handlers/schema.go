@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+
+	"go-env-cli/internal/app/models"
+	"go-env-cli/internal/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// SetVariableType declares the value type of a project's variable, e.g. "int" or
+// "enum(debug,info)". Values written to the variable afterwards must match it.
+func (h *EnvHandler) SetVariableType(projectName, key, typeDeclaration string) error {
+	valueType, err := utils.ParseValueType(typeDeclaration)
+	if err != nil {
+		return err
+	}
+
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.repo.SetVariableType(project.ID, key, valueType.String()); err != nil {
+		return fmt.Errorf("failed to set variable type: %w", err)
+	}
+
+	return nil
+}
+
+// SetVariableRequired marks a project's variable as required or optional in every environment
+func (h *EnvHandler) SetVariableRequired(projectName, key string, required bool) error {
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.repo.SetVariableRequired(project.ID, key, required); err != nil {
+		return fmt.Errorf("failed to set variable required: %w", err)
+	}
+
+	return nil
+}
+
+// ValidationResult describes the problems found by ValidateEnvironment
+type ValidationResult struct {
+	Project     string            `json:"project"`
+	Environment string            `json:"environment"`
+	Valid       bool              `json:"valid"`
+	Missing     []string          `json:"missing"`
+	Empty       []string          `json:"empty"`
+	Invalid     []ValidationIssue `json:"invalid"`
+}
+
+// ValidationIssue is a value that does not match its declared type
+type ValidationIssue struct {
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+// ValidateEnvironment checks that every required variable of a project is set to a
+// non-empty value in an environment, and that all values match their declared types
+func (h *EnvHandler) ValidateEnvironment(projectName, environmentName string) (*ValidationResult, error) {
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := h.findEnvironment(project, environmentName)
+	if err != nil {
+		return nil, err
+	}
+
+	required, err := h.repo.GetRequiredKeys(project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	types, err := h.valueTypes(project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	variables, err := h.repo.GetEnvVariables(project.ID, env.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environment variables: %w", err)
+	}
+
+	values := make(map[string]string, len(variables))
+	for _, v := range variables {
+		values[v.Key] = v.Value
+	}
+
+	result := &ValidationResult{
+		Project:     projectName,
+		Environment: environmentName,
+		Missing:     []string{},
+		Empty:       []string{},
+		Invalid:     []ValidationIssue{},
+	}
+
+	for _, key := range required {
+		value, ok := values[key]
+		switch {
+		case !ok:
+			result.Missing = append(result.Missing, key)
+		case value == "":
+			result.Empty = append(result.Empty, key)
+		}
+	}
+
+	for _, v := range variables {
+		valueType, ok := types[v.Key]
+		if !ok {
+			continue
+		}
+		if err := valueType.Validate(v.Key, v.Value); err != nil {
+			result.Invalid = append(result.Invalid, ValidationIssue{Key: v.Key, Message: err.Error()})
+		}
+	}
+
+	result.Valid = len(result.Missing) == 0 && len(result.Empty) == 0 && len(result.Invalid) == 0
+	return result, nil
+}
+
+// ListVariableTypes lists the value types declared for a project's variables
+func (h *EnvHandler) ListVariableTypes(projectName string) ([]models.VariableType, error) {
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.repo.GetVariableTypes(project.ID)
+}
+
+// DeleteVariableType removes the value type declared for a project's variable
+func (h *EnvHandler) DeleteVariableType(projectName, key string) error {
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	return h.repo.DeleteVariableType(project.ID, key)
+}
+
+// valueTypes loads the declared value types of a project's variables, keyed by variable key
+func (h *EnvHandler) valueTypes(projectID uuid.UUID) (map[string]utils.ValueType, error) {
+	variableTypes, err := h.repo.GetVariableTypes(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	types := make(map[string]utils.ValueType, len(variableTypes))
+	for _, t := range variableTypes {
+		valueType, err := utils.ParseValueType(t.Type)
+		if err != nil {
+			return nil, fmt.Errorf("invalid type declared for %s: %w", t.Key, err)
+		}
+		types[t.Key] = valueType
+	}
+
+	return types, nil
+}
+
+// validateValues checks values about to be written to a project's variables against
+// their declared types
+func (h *EnvHandler) validateValues(projectID uuid.UUID, values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	types, err := h.valueTypes(projectID)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if valueType, ok := types[key]; ok {
+			if err := valueType.Validate(key, values[key]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
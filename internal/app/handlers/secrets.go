@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"go-env-cli/internal/pkg/logger"
+	"go-env-cli/internal/pkg/utils"
+)
+
+// WarnIfSecret logs a warning when value looks like a real credential, such as an AWS
+// access key or a private key, and reports whether it did
+func WarnIfSecret(key, value string) bool {
+	kind, ok := utils.DetectSecret(value)
+	if ok {
+		logger.Warnf("%s looks like %s, which is stored in plaintext; consider keeping it in a secret manager instead", key, kind)
+	}
+	return ok
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import "go-env-cli/internal/app/models"
+
+// DefaultEnvironments are the environments created by SeedEnvironments, so commands
+// defaulting to --env development work on a new installation
+var DefaultEnvironments = []models.Environment{
+	{Name: "development", Description: "Development environment"},
+	{Name: "staging", Description: "Staging environment"},
+	{Name: "production", Description: "Production environment"},
+}
+
+// SeedEnvironments creates the DefaultEnvironments that don't exist yet, as shared
+// environments when projectName is empty or in the project otherwise. Seeding twice
+// changes nothing. It returns the names of the environments created.
+func (h *EnvHandler) SeedEnvironments(projectName string) ([]string, error) {
+	var created []string
+	for _, env := range DefaultEnvironments {
+		ok, err := h.EnsureEnvironment(projectName, env.Name, env.Description)
+		if err != nil {
+			return created, err
+		}
+		if ok {
+			created = append(created, env.Name)
+		}
+	}
+
+	return created, nil
+}
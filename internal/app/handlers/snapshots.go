@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"go-env-cli/internal/app/models"
+)
+
+// CreateSnapshot records every active variable of a project environment in a new snapshot
+func (h *EnvHandler) CreateSnapshot(projectName, environmentName, description string) (*models.Snapshot, error) {
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := h.writableEnvironment(project, environmentName)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.repo.CreateSnapshot(project.ID, env.ID, description)
+}
+
+// ListSnapshots lists the snapshots of a project environment, newest first
+func (h *EnvHandler) ListSnapshots(projectName, environmentName string) ([]models.Snapshot, error) {
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := h.findEnvironment(project, environmentName)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.repo.GetSnapshots(project.ID, env.ID)
+}
+
+// GetSnapshot gets a snapshot by ID
+func (h *EnvHandler) GetSnapshot(id int64) (*models.Snapshot, error) {
+	return h.repo.GetSnapshot(id)
+}
+
+// RestoreSnapshot makes the snapshot's project environment match it exactly and
+// returns the keys that were set and deleted
+func (h *EnvHandler) RestoreSnapshot(id int64) (*models.SnapshotRestore, error) {
+	return h.repo.RestoreSnapshot(id)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TagProject adds and removes tags of a project, such as "team:payments", and returns
+// the tags it has afterwards. A tag both added and removed ends up removed.
+func (h *EnvHandler) TagProject(projectName string, add, remove []string) ([]string, error) {
+	for _, tag := range append(append([]string{}, add...), remove...) {
+		if err := validateTag(tag); err != nil {
+			return nil, err
+		}
+	}
+
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(add) > 0 {
+		if err := h.repo.AddProjectTags(project.ID, add); err != nil {
+			return nil, err
+		}
+	}
+	if len(remove) > 0 {
+		if err := h.repo.RemoveProjectTags(project.ID, remove); err != nil {
+			return nil, err
+		}
+	}
+
+	return h.repo.GetProjectTags(project.ID)
+}
+
+// GetProjectTags gets the tags of a project
+func (h *EnvHandler) GetProjectTags(projectName string) ([]string, error) {
+	project, err := h.findProject(projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.repo.GetProjectTags(project.ID)
+}
+
+// validateTag returns an error if tag is empty or contains whitespace
+func validateTag(tag string) error {
+	if tag == "" {
+		return fmt.Errorf("tags must not be empty")
+	}
+	if strings.ContainsAny(tag, " \t\r\n") {
+		return fmt.Errorf("invalid tag '%s': tags must not contain whitespace", tag)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+
+	"go-env-cli/internal/app/models"
+)
+
+// RenderTemplate executes a Go text/template with the variables of a project
+// environment and writes the result to w. Variables are available as {{ .KEY }} and
+// {{ env "KEY" }}. A key the template uses that the environment doesn't have fails
+// the rendering, unless allowMissing is true, in which case it renders as an empty
+// string. name names the template in errors.
+func (h *EnvHandler) RenderTemplate(w io.Writer, projectName, environmentName, name, text string, allowMissing bool) error {
+	variables, err := h.ListEnvVariables(projectName, environmentName)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]string, len(variables))
+	for _, v := range variables {
+		values[v.Key] = v.Value
+	}
+
+	missingKey := "missingkey=error"
+	if allowMissing {
+		missingKey = "missingkey=zero"
+	}
+
+	tmpl, err := template.New(name).Option(missingKey).Funcs(template.FuncMap{
+		"env": func(key string) (string, error) {
+			value, ok := values[key]
+			if !ok && !allowMissing {
+				return "", fmt.Errorf("%w: %s", models.ErrVariableNotFound, key)
+			}
+			return value, nil
+		},
+	}).Parse(text)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	if err := tmpl.Execute(w, values); err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return nil
+}
+
+// RenderTemplateFile renders a template like RenderTemplate into a file. The file is
+// written atomically, so nothing is written when rendering fails.
+func (h *EnvHandler) RenderTemplateFile(filePath, projectName, environmentName, name, text string, allowMissing bool) error {
+	return writeFileAtomic(filePath, func(w io.Writer) error {
+		return h.RenderTemplate(w, projectName, environmentName, name, text, allowMissing)
+	})
+}
@@ -0,0 +1,415 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// BackupVersion is the version of the backup document format. Version 1 backups,
+// from before environments belonged to projects, only have shared environments.
+const BackupVersion = 2
+
+// Backup is a portable snapshot of every project, environment and variable.
+// Records reference each other by name rather than by ID so a backup can be
+// restored into a different database. Environments lists the shared environments;
+// the environments of a project are listed with the project.
+type Backup struct {
+	Version      int                 `json:"version"`
+	CreatedAt    time.Time           `json:"created_at"`
+	Environments []BackupEnvironment `json:"environments"`
+	Projects     []BackupProject     `json:"projects"`
+}
+
+// BackupEnvironment is an environment in a backup
+type BackupEnvironment struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Parent      string    `json:"parent,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// BackupProject is a project, its environments and its variables in a backup
+type BackupProject struct {
+	Name         string              `json:"name"`
+	Description  string              `json:"description"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+	DeletedAt    *time.Time          `json:"deleted_at,omitempty"`
+	Environments []BackupEnvironment `json:"environments,omitempty"`
+	Variables    []BackupVariable    `json:"variables"`
+}
+
+// BackupVariable is an environment variable in a backup
+type BackupVariable struct {
+	Environment string     `json:"environment"`
+	Key         string     `json:"key"`
+	Value       string     `json:"value"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+// DumpSteps is the number of steps Dump reports progress for, one per kind of record
+const DumpSteps = 3
+
+// Dump reads every project, environment and variable, including soft-deleted ones.
+// progress is told about each of the DumpSteps steps done.
+func (r *Repository) Dump(progress ProgressFunc) (*Backup, error) {
+	backup := &Backup{
+		Version:      BackupVersion,
+		CreatedAt:    time.Now(),
+		Environments: []BackupEnvironment{},
+		Projects:     []BackupProject{},
+	}
+
+	// Environments, with their parent resolved to a name
+	envQuery := `
+		SELECT e.project_id, e.name, COALESCE(e.description, '') AS description, COALESCE(p.name, '') AS parent,
+			e.created_at, e.updated_at
+		FROM environments e
+		LEFT JOIN environments p ON p.id = e.parent_id
+		ORDER BY e.name
+	`
+	var environments []struct {
+		ProjectID   *uuid.UUID `db:"project_id"`
+		Name        string     `db:"name"`
+		Description string     `db:"description"`
+		Parent      string     `db:"parent"`
+		CreatedAt   time.Time  `db:"created_at"`
+		UpdatedAt   time.Time  `db:"updated_at"`
+	}
+	progress.Report(0, DumpSteps)
+	if err := r.db.Select(&environments, envQuery); err != nil {
+		return nil, fmt.Errorf("failed to get environments: %w", err)
+	}
+	progress.Report(1, DumpSteps)
+
+	environmentsByProject := make(map[uuid.UUID][]BackupEnvironment)
+	for _, e := range environments {
+		environment := BackupEnvironment{
+			Name:        e.Name,
+			Description: e.Description,
+			Parent:      e.Parent,
+			CreatedAt:   e.CreatedAt,
+			UpdatedAt:   e.UpdatedAt,
+		}
+		if e.ProjectID == nil {
+			backup.Environments = append(backup.Environments, environment)
+		} else {
+			environmentsByProject[*e.ProjectID] = append(environmentsByProject[*e.ProjectID], environment)
+		}
+	}
+
+	// Projects
+	projects := []Project{}
+	projectQuery := `
+		SELECT id, name, COALESCE(description, '') AS description, created_at, updated_at, deleted_at
+		FROM projects
+		ORDER BY name, deleted_at NULLS FIRST
+	`
+	if err := r.db.Select(&projects, projectQuery); err != nil {
+		return nil, fmt.Errorf("failed to get projects: %w", err)
+	}
+	progress.Report(2, DumpSteps)
+
+	// Variables, grouped by project
+	var variables []struct {
+		ProjectID   uuid.UUID  `db:"project_id"`
+		Environment string     `db:"environment"`
+		Key         string     `db:"key"`
+		Value       string     `db:"value"`
+		CreatedAt   time.Time  `db:"created_at"`
+		UpdatedAt   time.Time  `db:"updated_at"`
+		DeletedAt   *time.Time `db:"deleted_at"`
+	}
+	variableQuery := `
+		SELECT ev.project_id, e.name AS environment, ev.key, COALESCE(ev.value, '') AS value,
+			ev.created_at, ev.updated_at, ev.deleted_at
+		FROM env_variables ev
+		JOIN environments e ON e.id = ev.environment_id
+		ORDER BY e.name, ev.key, ev.deleted_at NULLS FIRST
+	`
+	if err := r.db.Select(&variables, variableQuery); err != nil {
+		return nil, fmt.Errorf("failed to get environment variables: %w", err)
+	}
+	progress.Report(3, DumpSteps)
+
+	byProject := make(map[uuid.UUID][]BackupVariable)
+	for _, v := range variables {
+		byProject[v.ProjectID] = append(byProject[v.ProjectID], BackupVariable{
+			Environment: v.Environment,
+			Key:         v.Key,
+			Value:       v.Value,
+			CreatedAt:   v.CreatedAt,
+			UpdatedAt:   v.UpdatedAt,
+			DeletedAt:   v.DeletedAt,
+		})
+	}
+
+	for _, p := range projects {
+		projectVariables := byProject[p.ID]
+		if projectVariables == nil {
+			projectVariables = []BackupVariable{}
+		}
+		backup.Projects = append(backup.Projects, BackupProject{
+			Name:         p.Name,
+			Description:  p.Description,
+			CreatedAt:    p.CreatedAt,
+			UpdatedAt:    p.UpdatedAt,
+			DeletedAt:    p.DeletedAt,
+			Environments: environmentsByProject[p.ID],
+			Variables:    projectVariables,
+		})
+	}
+
+	return backup, nil
+}
+
+// Restore recreates the contents of a backup in a single transaction. Records are
+// matched by name (environments, projects) and key (variables) and updated in place
+// when they already exist, so restoring the same backup twice is harmless. A project
+// without an environment its variables use gets a copy of the shared environment of
+// that name, as in backups of version 1. progress is told about every variable restored.
+func (r *Repository) Restore(backup *Backup, progress ProgressFunc) (err error) {
+	if backup.Version < 1 || backup.Version > BackupVersion {
+		return fmt.Errorf("unsupported backup version %d", backup.Version)
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Shared environments first
+	if _, err = restoreEnvironments(tx, nil, backup.Environments); err != nil {
+		return err
+	}
+
+	shared := make(map[string]BackupEnvironment, len(backup.Environments))
+	for _, e := range backup.Environments {
+		shared[e.Name] = e
+	}
+
+	total := 0
+	for _, p := range backup.Projects {
+		total += len(p.Variables)
+	}
+	restored := 0
+	progress.Report(restored, total)
+
+	// Projects, their environments and their variables
+	for _, p := range backup.Projects {
+		projectID, err := restoreProject(tx, p)
+		if err != nil {
+			return err
+		}
+
+		environmentIDs, err := restoreEnvironments(tx, &projectID, p.Environments)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range p.Variables {
+			environmentID, err := adoptBackupEnvironment(tx, projectID, environmentIDs, shared, v.Environment)
+			if err != nil {
+				return fmt.Errorf("variable %s of project %s: %w", v.Key, p.Name, err)
+			}
+
+			if err := restoreVariable(tx, projectID, environmentID, v); err != nil {
+				return err
+			}
+			restored++
+			progress.Report(restored, total)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// restoreEnvironments upserts the environments of a project, or shared environments
+// when projectID is nil, then sets their parents once every environment exists. It
+// returns the IDs of the environments by name.
+func restoreEnvironments(tx *sqlx.Tx, projectID *uuid.UUID, environments []BackupEnvironment) (map[string]uuid.UUID, error) {
+	environmentIDs := make(map[string]uuid.UUID, len(environments))
+	for _, e := range environments {
+		id, err := restoreEnvironment(tx, projectID, e)
+		if err != nil {
+			return nil, err
+		}
+		environmentIDs[e.Name] = id
+	}
+
+	for _, e := range environments {
+		var parentID *uuid.UUID
+		if e.Parent != "" {
+			id, ok := environmentIDs[e.Parent]
+			if !ok {
+				return nil, fmt.Errorf("environment '%s' has unknown parent '%s'", e.Name, e.Parent)
+			}
+			parentID = &id
+		}
+
+		_, err := tx.Exec(`UPDATE environments SET parent_id = $1 WHERE id = $2`, parentID, environmentIDs[e.Name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore parent of environment %s: %w", e.Name, err)
+		}
+	}
+
+	return environmentIDs, nil
+}
+
+// adoptBackupEnvironment returns the ID of the environment of a project named name,
+// restoring a project copy of the shared environment of that name, and of its
+// ancestors, when environmentIDs doesn't have it. Copies are added to environmentIDs.
+func adoptBackupEnvironment(tx *sqlx.Tx, projectID uuid.UUID, environmentIDs map[string]uuid.UUID, shared map[string]BackupEnvironment, name string) (uuid.UUID, error) {
+	if id, ok := environmentIDs[name]; ok {
+		return id, nil
+	}
+
+	e, ok := shared[name]
+	if !ok {
+		return uuid.Nil, fmt.Errorf("unknown environment '%s'", name)
+	}
+
+	id, err := restoreEnvironment(tx, &projectID, e)
+	if err != nil {
+		return id, err
+	}
+	environmentIDs[name] = id
+
+	if e.Parent != "" {
+		parentID, err := adoptBackupEnvironment(tx, projectID, environmentIDs, shared, e.Parent)
+		if err != nil {
+			return id, err
+		}
+
+		_, err = tx.Exec(`UPDATE environments SET parent_id = $1 WHERE id = $2`, parentID, id)
+		if err != nil {
+			return id, fmt.Errorf("failed to restore parent of environment %s: %w", e.Name, err)
+		}
+	}
+
+	return id, nil
+}
+
+// restoreEnvironment upserts an environment of a project, or a shared environment when
+// projectID is nil, by name, ignoring case, and returns its ID
+func restoreEnvironment(tx *sqlx.Tx, projectID *uuid.UUID, e BackupEnvironment) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := tx.Get(&id, `
+		SELECT id FROM environments
+		WHERE project_id IS NOT DISTINCT FROM $1 AND LOWER(name) = LOWER($2)
+	`, projectID, e.Name)
+	if err == nil {
+		_, err = tx.Exec(`UPDATE environments SET description = $1, updated_at = $2 WHERE id = $3`,
+			e.Description, e.UpdatedAt, id)
+		if err != nil {
+			return id, fmt.Errorf("failed to restore environment %s: %w", e.Name, err)
+		}
+		return id, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return id, fmt.Errorf("failed to check existing environment %s: %w", e.Name, err)
+	}
+
+	id = uuid.New()
+	_, err = tx.Exec(`
+		INSERT INTO environments (id, project_id, name, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, id, projectID, e.Name, e.Description, e.CreatedAt, e.UpdatedAt)
+	if err != nil {
+		return id, fmt.Errorf("failed to restore environment %s: %w", e.Name, err)
+	}
+
+	return id, nil
+}
+
+// restoreProject upserts a project and returns its ID. Active projects are matched by
+// name; soft-deleted projects by name and deletion time.
+func restoreProject(tx *sqlx.Tx, p BackupProject) (uuid.UUID, error) {
+	var id uuid.UUID
+	var err error
+	if p.DeletedAt == nil {
+		err = tx.Get(&id, `SELECT id FROM projects WHERE name = $1 AND deleted_at IS NULL`, p.Name)
+	} else {
+		err = tx.Get(&id, `SELECT id FROM projects WHERE name = $1 AND deleted_at = $2`, p.Name, *p.DeletedAt)
+	}
+
+	if err == nil {
+		_, err = tx.Exec(`UPDATE projects SET description = $1, updated_at = $2 WHERE id = $3`,
+			p.Description, p.UpdatedAt, id)
+		if err != nil {
+			return id, fmt.Errorf("failed to restore project %s: %w", p.Name, err)
+		}
+		return id, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return id, fmt.Errorf("failed to check existing project %s: %w", p.Name, err)
+	}
+
+	id = uuid.New()
+	_, err = tx.Exec(`
+		INSERT INTO projects (id, name, description, created_at, updated_at, deleted_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, id, p.Name, p.Description, p.CreatedAt, p.UpdatedAt, p.DeletedAt)
+	if err != nil {
+		return id, fmt.Errorf("failed to restore project %s: %w", p.Name, err)
+	}
+
+	return id, nil
+}
+
+// restoreVariable upserts a variable. Active variables are matched by key; soft-deleted
+// variables by key and deletion time.
+func restoreVariable(tx *sqlx.Tx, projectID, environmentID uuid.UUID, v BackupVariable) error {
+	var id uuid.UUID
+	var err error
+	if v.DeletedAt == nil {
+		err = tx.Get(&id, `
+			SELECT id FROM env_variables
+			WHERE project_id = $1 AND environment_id = $2 AND key = $3 AND deleted_at IS NULL
+		`, projectID, environmentID, v.Key)
+	} else {
+		err = tx.Get(&id, `
+			SELECT id FROM env_variables
+			WHERE project_id = $1 AND environment_id = $2 AND key = $3 AND deleted_at = $4
+		`, projectID, environmentID, v.Key, *v.DeletedAt)
+	}
+
+	if err == nil {
+		_, err = tx.Exec(`UPDATE env_variables SET value = $1, updated_at = $2 WHERE id = $3`,
+			v.Value, v.UpdatedAt, id)
+		if err != nil {
+			return fmt.Errorf("failed to restore environment variable %s: %w", v.Key, err)
+		}
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to check existing environment variable %s: %w", v.Key, err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO env_variables (id, project_id, environment_id, key, value, created_at, updated_at, deleted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, uuid.New(), projectID, environmentID, v.Key, v.Value, v.CreatedAt, v.UpdatedAt, v.DeletedAt)
+	if err != nil {
+		return fmt.Errorf("failed to restore environment variable %s: %w", v.Key, err)
+	}
+
+	return nil
+}
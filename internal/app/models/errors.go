@@ -0,0 +1,18 @@
+package models
+
+import "errors"
+
+// Errors returned when a lookup matches no active record. They are wrapped with the
+// name or key that was looked up, so callers should test for them with errors.Is.
+var (
+	ErrProjectNotFound     = errors.New("project not found")
+	ErrEnvironmentNotFound = errors.New("environment not found")
+	ErrVariableNotFound    = errors.New("environment variable not found")
+	ErrSnapshotNotFound    = errors.New("snapshot not found")
+)
+
+// ErrAlreadyExists is wrapped by errors about a name or key that is already taken
+var ErrAlreadyExists = errors.New("already exists")
+
+// ErrNothingToUndo is returned when an environment has no recorded change left to undo
+var ErrNothingToUndo = errors.New("nothing to undo")
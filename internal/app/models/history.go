@@ -0,0 +1,116 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// recordHistory appends a change to the variable history using the given database
+// handle or transaction
+func recordHistory(db sqlx.Ext, entry HistoryEntry) error {
+	query := `
+		INSERT INTO variable_history (project_id, environment_id, operation, key, old_key, old_value, new_value, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8)
+	`
+
+	_, err := db.Exec(query, entry.ProjectID, entry.EnvironmentID, entry.Operation, entry.Key,
+		entry.OldKey, entry.OldValue, entry.NewValue, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to record variable history: %w", err)
+	}
+
+	return nil
+}
+
+// lastHistoryQuery selects the most recent change of a project environment that hasn't been undone
+const lastHistoryQuery = `
+	SELECT id, project_id, environment_id, operation, key, COALESCE(old_key, '') AS old_key,
+		old_value, new_value, created_at, undone_at
+	FROM variable_history
+	WHERE project_id = $1 AND environment_id = $2 AND undone_at IS NULL
+	ORDER BY id DESC
+	LIMIT 1
+`
+
+// GetLastHistoryEntry gets the most recent change of a project environment that hasn't
+// been undone
+func (r *Repository) GetLastHistoryEntry(projectID, environmentID uuid.UUID) (*HistoryEntry, error) {
+	entry := &HistoryEntry{}
+	err := r.db.Get(entry, lastHistoryQuery, projectID, environmentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNothingToUndo
+		}
+		return nil, fmt.Errorf("failed to get variable history: %w", err)
+	}
+
+	return entry, nil
+}
+
+// UndoHistoryEntry applies the inverse of a recorded change and marks it as undone in a
+// single transaction. It fails if the entry is no longer the most recent change of its
+// project environment, so a change made after it was shown is never skipped over.
+// The inverse itself isn't recorded, so undoing repeatedly steps further back.
+func (r *Repository) UndoHistoryEntry(id int64) (err error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	entry := &HistoryEntry{}
+	err = tx.Get(entry, `
+		SELECT id, project_id, environment_id, operation, key, COALESCE(old_key, '') AS old_key,
+			old_value, new_value, created_at, undone_at
+		FROM variable_history
+		WHERE id = $1
+		FOR UPDATE
+	`, id)
+	if err != nil {
+		return fmt.Errorf("failed to get variable history: %w", err)
+	}
+
+	last := &HistoryEntry{}
+	if err = tx.Get(last, lastHistoryQuery, entry.ProjectID, entry.EnvironmentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNothingToUndo
+		}
+		return fmt.Errorf("failed to get variable history: %w", err)
+	}
+	if last.ID != entry.ID {
+		return fmt.Errorf("the environment changed since, %s %s is now the most recent change", last.Operation, last.Key)
+	}
+
+	switch {
+	case entry.Operation == HistoryRename:
+		err = renameEnvVariable(tx, entry.ProjectID, entry.EnvironmentID, entry.Key, entry.OldKey)
+	case entry.Operation == HistorySet && entry.OldValue == nil:
+		_, err = removeEnvVariable(tx, entry.ProjectID, entry.EnvironmentID, entry.Key)
+	case entry.OldValue != nil:
+		_, _, err = writeEnvVariable(tx, entry.ProjectID, entry.EnvironmentID, entry.Key, *entry.OldValue)
+	default:
+		err = fmt.Errorf("unknown operation '%s'", entry.Operation)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to undo %s %s: %w", entry.Operation, entry.Key, err)
+	}
+
+	if _, err = tx.Exec(`UPDATE variable_history SET undone_at = $1 WHERE id = $2`, time.Now(), entry.ID); err != nil {
+		return fmt.Errorf("failed to mark change as undone: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -16,13 +16,23 @@ type Project struct {
 	DeletedAt   *time.Time `db:"deleted_at" json:"deleted_at"`
 }
 
+// ProjectQuery selects, orders and limits the projects returned by QueryProjects
+type ProjectQuery struct {
+	Pattern string   // case-insensitive substring of the name, empty for all projects
+	Sort    string   // "name" (default), "created" or "updated", newest first
+	Limit   int      // maximum number of projects, 0 for no limit
+	Tags    []string // tags every returned project must have
+}
+
 // Environment represents an environment type (development, sit, uat, etc.)
 type Environment struct {
-	ID          uuid.UUID `db:"id" json:"id"`
-	Name        string    `db:"name" json:"name"`
-	Description string    `db:"description" json:"description"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	ID          uuid.UUID  `db:"id" json:"id"`
+	ProjectID   *uuid.UUID `db:"project_id" json:"project_id"`
+	Name        string     `db:"name" json:"name"`
+	Description string     `db:"description" json:"description"`
+	ParentID    *uuid.UUID `db:"parent_id" json:"parent_id"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
 }
 
 // EnvVariable represents a single environment variable
@@ -35,6 +45,128 @@ type EnvVariable struct {
 	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt     time.Time  `db:"updated_at" json:"updated_at"`
 	DeletedAt     *time.Time `db:"deleted_at" json:"deleted_at"`
+
+	// Position is the place of the variable in the file it was last imported from,
+	// nil when it wasn't imported. Only loaded by GetEnvVariables.
+	Position *int `db:"position" json:"position,omitempty"`
+}
+
+// VariableType represents the declared value type of a project's variable, such as
+// "int" or "enum(debug,info)", and whether every environment must set it
+type VariableType struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	ProjectID uuid.UUID `db:"project_id" json:"project_id"`
+	Key       string    `db:"key" json:"key"`
+	Type      string    `db:"type" json:"type"`
+	Required  bool      `db:"required" json:"required"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Snapshot represents a point-in-time copy of every active variable of a project environment
+type Snapshot struct {
+	ID            int64     `db:"id" json:"id"`
+	ProjectID     uuid.UUID `db:"project_id" json:"project_id"`
+	EnvironmentID uuid.UUID `db:"environment_id" json:"environment_id"`
+	Description   string    `db:"description" json:"description"`
+	Variables     int       `db:"variables" json:"variables"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+}
+
+// SnapshotRestore represents the changes made by restoring a snapshot
+type SnapshotRestore struct {
+	Set     []string `json:"set"`
+	Deleted []string `json:"deleted"`
+}
+
+// Operations recorded in the variable history
+const (
+	HistorySet    = "set"
+	HistoryDelete = "delete"
+	HistoryRename = "rename"
+)
+
+// HistoryEntry represents a recorded change to a variable. OldValue is nil when a set
+// created the variable, and NewValue is nil for deletes. Renames record the new key in
+// Key and the previous one in OldKey.
+type HistoryEntry struct {
+	ID            int64      `db:"id" json:"id"`
+	ProjectID     uuid.UUID  `db:"project_id" json:"project_id"`
+	EnvironmentID uuid.UUID  `db:"environment_id" json:"environment_id"`
+	Operation     string     `db:"operation" json:"operation"`
+	Key           string     `db:"key" json:"key"`
+	OldKey        string     `db:"old_key" json:"old_key,omitempty"`
+	OldValue      *string    `db:"old_value" json:"old_value"`
+	NewValue      *string    `db:"new_value" json:"new_value"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	UndoneAt      *time.Time `db:"undone_at" json:"undone_at"`
+}
+
+// BatchChange represents one set, delete or rename applied by ApplyBatch. Line is the
+// line of the batch file it came from, used in errors. EnvironmentID may name a shared
+// environment, which ApplyBatch copies into the project first.
+type BatchChange struct {
+	Line          int
+	ProjectID     uuid.UUID
+	EnvironmentID uuid.UUID
+	Operation     string // HistorySet, HistoryDelete or HistoryRename
+	Key           string
+	Value         string // value to set
+	NewKey        string // key to rename to
+}
+
+// ImportSummary counts what an import did with its entries. Skipped entries were
+// overridden by a later entry for the same key and environment.
+type ImportSummary struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Skipped   int `json:"skipped"`
+}
+
+// Total returns the number of variables the import stored or left unchanged
+func (s ImportSummary) Total() int {
+	return s.Created + s.Updated + s.Unchanged
+}
+
+// ProgressFunc is told how many of the total items of a long operation are done
+type ProgressFunc func(done, total int)
+
+// Report calls the function, if there is one
+func (f ProgressFunc) Report(done, total int) {
+	if f != nil {
+		f(done, total)
+	}
+}
+
+// EnvVariableMatch represents an environment variable found by a search across all
+// projects and environments
+type EnvVariableMatch struct {
+	ProjectName     string `db:"project_name" json:"project_name"`
+	EnvironmentName string `db:"environment_name" json:"environment_name"`
+	Key             string `db:"key" json:"key"`
+	Value           string `db:"value" json:"value"`
+}
+
+// VariableCount represents the number of active variables grouped by a project or environment name
+type VariableCount struct {
+	Name  string `db:"name" json:"name"`
+	Count int    `db:"count" json:"count"`
+}
+
+// Stats represents aggregate counts across the database
+type Stats struct {
+	Projects     int             `json:"projects"`
+	Environments int             `json:"environments"`
+	Variables    int             `json:"variables"`
+	PerProject   []VariableCount `json:"per_project"`
+}
+
+// PurgeCounts represents the number of soft-deleted rows permanently removed, or that
+// would be removed, by garbage collection
+type PurgeCounts struct {
+	Projects  int64 `json:"projects"`
+	Variables int64 `json:"variables"`
 }
 
 // ProjectWithEnv represents a project with its environment variables
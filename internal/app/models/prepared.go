@@ -0,0 +1,87 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// preparedTx runs the queries of a transaction through prepared statements kept for
+// the life of the transaction, so a loop that calls the same helpers for every
+// variable has each query parsed once instead of on every call. It satisfies
+// sqlx.Ext, so helpers taking one use the prepared statements unchanged. Close the
+// statements with Close once the loop is done.
+type preparedTx struct {
+	*sqlx.Tx
+	stmts map[string]*sqlx.Stmt
+}
+
+// newPreparedTx wraps a transaction so its queries are prepared on first use
+func newPreparedTx(tx *sqlx.Tx) *preparedTx {
+	return &preparedTx{Tx: tx, stmts: make(map[string]*sqlx.Stmt)}
+}
+
+// stmt returns the prepared statement of query, preparing it the first time
+func (p *preparedTx) stmt(query string) (*sqlx.Stmt, error) {
+	if stmt, ok := p.stmts[query]; ok {
+		return stmt, nil
+	}
+
+	stmt, err := p.Tx.Preparex(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	p.stmts[query] = stmt
+
+	return stmt, nil
+}
+
+// Exec executes query through its prepared statement
+func (p *preparedTx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	stmt, err := p.stmt(query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Exec(args...)
+}
+
+// Query runs query through its prepared statement
+func (p *preparedTx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	stmt, err := p.stmt(query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Query(args...)
+}
+
+// Queryx runs query through its prepared statement
+func (p *preparedTx) Queryx(query string, args ...interface{}) (*sqlx.Rows, error) {
+	stmt, err := p.stmt(query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Queryx(args...)
+}
+
+// QueryRowx runs query through its prepared statement. A statement that can't be
+// prepared is run directly, which reports the error when the row is scanned.
+func (p *preparedTx) QueryRowx(query string, args ...interface{}) *sqlx.Row {
+	stmt, err := p.stmt(query)
+	if err != nil {
+		return p.Tx.QueryRowx(query, args...)
+	}
+	return stmt.QueryRowx(args...)
+}
+
+// Close closes the prepared statements
+func (p *preparedTx) Close() error {
+	var firstErr error
+	for query, stmt := range p.stmts {
+		if err := stmt.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(p.stmts, query)
+	}
+	return firstErr
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BenchmarkPreparedTx measures what preparing the queries of a transaction once saves,
+// setting 1000 new variables per iteration with the queries sent as is or through a
+// preparedTx:
+//
+//	GO_CLI_TEST_DB=... go test ./internal/app/models -run '^$' -bench PreparedTx
+func BenchmarkPreparedTx(b *testing.B) {
+	r := testRepository(b)
+	project := testProject(b, r)
+
+	for _, prepared := range []bool{false, true} {
+		b.Run(fmt.Sprintf("prepared=%v", prepared), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				env, err := r.CreateEnvironment(&project.ID, fmt.Sprintf("bench-%v-%d", prepared, i), "")
+				if err != nil {
+					b.Fatalf("creating environment: %v", err)
+				}
+				variables := benchmarkVariables(env.ID, 1000)
+				b.StartTimer()
+
+				tx, err := r.db.Beginx()
+				if err != nil {
+					b.Fatalf("starting transaction: %v", err)
+				}
+				var db sqlx.Ext = tx
+				stmts := newPreparedTx(tx)
+				if prepared {
+					db = stmts
+				}
+				for _, v := range variables {
+					if _, err := setEnvVariable(db, project.ID, v.EnvironmentID, v.Key, v.Value); err != nil {
+						tx.Rollback()
+						b.Fatalf("setting %s: %v", v.Key, err)
+					}
+				}
+				stmts.Close()
+				if err := tx.Commit(); err != nil {
+					b.Fatalf("committing: %v", err)
+				}
+			}
+		})
+	}
+}
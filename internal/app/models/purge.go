@@ -0,0 +1,94 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// purgeableVariables matches variables deleted before the cutoff ($1) and all variables
+// of projects deleted before it
+const purgeableVariables = `
+	(deleted_at IS NOT NULL AND deleted_at < $1)
+	OR project_id IN (SELECT id FROM projects WHERE deleted_at < $1)
+`
+
+// CountPurgeable counts the soft-deleted projects and variables deleted before cutoff
+func (r *Repository) CountPurgeable(cutoff time.Time) (*PurgeCounts, error) {
+	counts := &PurgeCounts{}
+
+	err := r.db.Get(&counts.Projects, `SELECT COUNT(*) FROM projects WHERE deleted_at < $1`, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count deleted projects: %w", err)
+	}
+
+	err = r.db.Get(&counts.Variables, `SELECT COUNT(*) FROM env_variables WHERE `+purgeableVariables, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count deleted environment variables: %w", err)
+	}
+
+	return counts, nil
+}
+
+// PurgeDeleted permanently removes the soft-deleted projects and variables deleted
+// before cutoff, along with everything belonging to those projects, in a single transaction
+func (r *Repository) PurgeDeleted(cutoff time.Time) (counts *PurgeCounts, err error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	counts = &PurgeCounts{}
+
+	// Variables first, since they reference the projects
+	result, err := tx.Exec(`DELETE FROM env_variables WHERE `+purgeableVariables, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge environment variables: %w", err)
+	}
+	if counts.Variables, err = result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	_, err = tx.Exec(`DELETE FROM variable_types WHERE project_id IN (SELECT id FROM projects WHERE deleted_at < $1)`, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge variable types: %w", err)
+	}
+
+	_, err = tx.Exec(`DELETE FROM variable_history WHERE project_id IN (SELECT id FROM projects WHERE deleted_at < $1)`, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge variable history: %w", err)
+	}
+
+	_, err = tx.Exec(`DELETE FROM env_snapshots WHERE project_id IN (SELECT id FROM projects WHERE deleted_at < $1)`, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge snapshots: %w", err)
+	}
+
+	_, err = tx.Exec(`DELETE FROM project_tags WHERE project_id IN (SELECT id FROM projects WHERE deleted_at < $1)`, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge project tags: %w", err)
+	}
+
+	_, err = tx.Exec(`DELETE FROM environments WHERE project_id IN (SELECT id FROM projects WHERE deleted_at < $1)`, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge environments: %w", err)
+	}
+
+	result, err = tx.Exec(`DELETE FROM projects WHERE deleted_at < $1`, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge projects: %w", err)
+	}
+	if counts.Projects, err = result.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,365 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go-env-cli/internal/pkg/db"
+
+	"github.com/google/uuid"
+)
+
+// testRepository returns a repository on the database named by GO_CLI_TEST_DB,
+// migrated to the latest schema, and skips the test when it isn't set
+func testRepository(tb testing.TB) *Repository {
+	tb.Helper()
+
+	dsn := os.Getenv("GO_CLI_TEST_DB")
+	if dsn == "" {
+		tb.Skip("GO_CLI_TEST_DB is not set")
+	}
+
+	conn, err := db.NewDB(db.Config{GO_CLI_DB: dsn})
+	if err != nil {
+		tb.Fatalf("connecting to the test database: %v", err)
+	}
+	tb.Cleanup(func() { conn.Close() })
+
+	migrations, err := db.NewMigrationManager(conn, filepath.Join("..", "..", "..", "db", "migrations"))
+	if err != nil {
+		tb.Fatalf("reading migrations: %v", err)
+	}
+	if err := migrations.MigrateUp(); err != nil {
+		tb.Fatalf("migrating the test database: %v", err)
+	}
+
+	return NewRepository(conn)
+}
+
+// testProject creates a project with a unique name, soft-deleted when the test ends
+func testProject(tb testing.TB, r *Repository) *Project {
+	tb.Helper()
+
+	project, err := r.CreateProject("test-"+uuid.NewString(), "")
+	if err != nil {
+		tb.Fatalf("creating project: %v", err)
+	}
+	tb.Cleanup(func() { r.SoftDeleteProject(project.ID) })
+
+	return project
+}
+
+// testEnvironment creates an environment named "test" in project
+func testEnvironment(tb testing.TB, r *Repository, project *Project) *Environment {
+	tb.Helper()
+
+	env, err := r.CreateEnvironment(&project.ID, "test", "")
+	if err != nil {
+		tb.Fatalf("creating environment: %v", err)
+	}
+
+	return env
+}
+
+// envValues returns the active variables of an environment as a map
+func envValues(tb testing.TB, r *Repository, projectID, environmentID uuid.UUID) map[string]string {
+	tb.Helper()
+
+	variables, err := r.GetEnvVariables(projectID, environmentID)
+	if err != nil {
+		tb.Fatalf("getting variables: %v", err)
+	}
+
+	values := make(map[string]string, len(variables))
+	for _, v := range variables {
+		values[v.Key] = v.Value
+	}
+	return values
+}
+
+func TestCreateEnvironmentIgnoresCase(t *testing.T) {
+	r := testRepository(t)
+	project := testProject(t, r)
+
+	if _, err := r.CreateEnvironment(&project.ID, "prod", ""); err != nil {
+		t.Fatalf("creating prod: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"prod", true},
+		{"PROD", true},
+		{"Prod", true},
+		{"prod2", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := r.CreateEnvironment(&project.ID, tt.name, "")
+			if tt.wantErr && !errors.Is(err, ErrAlreadyExists) {
+				t.Errorf("CreateEnvironment(%q) error = %v, want ErrAlreadyExists", tt.name, err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("CreateEnvironment(%q) error = %v", tt.name, err)
+			}
+		})
+	}
+
+	// The unique index of migration 11 rejects it too, without the check above
+	_, err := r.db.Exec(`INSERT INTO environments (id, project_id, name) VALUES ($1, $2, 'PROD')`, uuid.New(), project.ID)
+	if err == nil {
+		t.Error("inserting PROD next to prod succeeded, want a unique violation")
+	}
+
+	// Another project can use the same name
+	other := testProject(t, r)
+	if _, err := r.CreateEnvironment(&other.ID, "PROD", ""); err != nil {
+		t.Errorf("creating PROD in another project: %v", err)
+	}
+}
+
+func TestAdoptEnvironment(t *testing.T) {
+	r := testRepository(t)
+	project := testProject(t, r)
+
+	suffix := uuid.NewString()
+	base, err := r.CreateEnvironment(nil, "base-"+suffix, "")
+	if err != nil {
+		t.Fatalf("creating base: %v", err)
+	}
+	shared, err := r.CreateEnvironment(nil, "prod-"+suffix, "production")
+	if err != nil {
+		t.Fatalf("creating prod: %v", err)
+	}
+	if err := r.SetEnvironmentParent(shared.ID, &base.ID); err != nil {
+		t.Fatalf("setting parent: %v", err)
+	}
+	shared.ParentID = &base.ID
+
+	env, err := r.AdoptEnvironment(project.ID, shared)
+	if err != nil {
+		t.Fatalf("AdoptEnvironment() error = %v", err)
+	}
+	if env.ProjectID == nil || *env.ProjectID != project.ID || env.Name != shared.Name || env.Description != "production" {
+		t.Errorf("AdoptEnvironment() = %+v, want a copy of %s in the project", env, shared.Name)
+	}
+
+	parent, err := r.GetEnvironmentByName(&project.ID, base.Name)
+	if err != nil {
+		t.Fatalf("getting the adopted parent: %v", err)
+	}
+	if env.ParentID == nil || *env.ParentID != parent.ID {
+		t.Errorf("ParentID = %v, want the project's copy %s", env.ParentID, parent.ID)
+	}
+
+	// Adopting again reuses the copy
+	again, err := r.AdoptEnvironment(project.ID, shared)
+	if err != nil {
+		t.Fatalf("AdoptEnvironment() again error = %v", err)
+	}
+	if again.ID != env.ID {
+		t.Errorf("AdoptEnvironment() again = %s, want %s", again.ID, env.ID)
+	}
+}
+
+func TestApplyBatchAdoptsEnvironment(t *testing.T) {
+	r := testRepository(t)
+	project := testProject(t, r)
+	shared, err := r.CreateEnvironment(nil, "shared-"+uuid.NewString(), "")
+	if err != nil {
+		t.Fatalf("creating shared environment: %v", err)
+	}
+
+	// A failing line leaves no copy behind
+	err = r.ApplyBatch([]BatchChange{
+		{Line: 1, ProjectID: project.ID, EnvironmentID: shared.ID, Operation: HistorySet, Key: "A", Value: "a"},
+		{Line: 2, ProjectID: project.ID, EnvironmentID: shared.ID, Operation: HistoryRename, Key: "MISSING", NewKey: "B"},
+	})
+	if !errors.Is(err, ErrVariableNotFound) {
+		t.Fatalf("ApplyBatch() error = %v, want ErrVariableNotFound", err)
+	}
+	if _, err := r.GetEnvironmentByName(&project.ID, shared.Name); !errors.Is(err, ErrEnvironmentNotFound) {
+		t.Errorf("getting the copy after a failed batch: error = %v, want ErrEnvironmentNotFound", err)
+	}
+
+	err = r.ApplyBatch([]BatchChange{
+		{Line: 1, ProjectID: project.ID, EnvironmentID: shared.ID, Operation: HistorySet, Key: "A", Value: "a"},
+		{Line: 2, ProjectID: project.ID, EnvironmentID: shared.ID, Operation: HistoryRename, Key: "A", NewKey: "B"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyBatch() error = %v", err)
+	}
+
+	env, err := r.GetEnvironmentByName(&project.ID, shared.Name)
+	if err != nil {
+		t.Fatalf("getting the copy: %v", err)
+	}
+	if got, want := envValues(t, r, project.ID, env.ID), map[string]string{"B": "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("copy variables = %v, want %v", got, want)
+	}
+	if got := envValues(t, r, project.ID, shared.ID); len(got) != 0 {
+		t.Errorf("shared environment variables = %v, want none", got)
+	}
+}
+
+func TestRenameEnvVariables(t *testing.T) {
+	tests := []struct {
+		name    string
+		renames map[string]string
+		want    map[string]string
+	}{
+		{"rename", map[string]string{"A": "X"}, map[string]string{"X": "a", "B": "b", "C": "c"}},
+		{"chain", map[string]string{"A": "B", "B": "D"}, map[string]string{"B": "a", "D": "b", "C": "c"}},
+		{"swap", map[string]string{"A": "B", "B": "A"}, map[string]string{"A": "b", "B": "a", "C": "c"}},
+		{"cycle", map[string]string{"A": "B", "B": "C", "C": "A"}, map[string]string{"A": "c", "B": "a", "C": "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testRepository(t)
+			project := testProject(t, r)
+			env := testEnvironment(t, r, project)
+			for key, value := range map[string]string{"A": "a", "B": "b", "C": "c"} {
+				if _, err := r.SetEnvVariable(project.ID, env.ID, key, value); err != nil {
+					t.Fatalf("setting %s: %v", key, err)
+				}
+			}
+
+			if err := r.RenameEnvVariables(project.ID, env.ID, tt.renames); err != nil {
+				t.Fatalf("RenameEnvVariables() error = %v", err)
+			}
+			if got := envValues(t, r, project.ID, env.ID); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("variables = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenameEnvVariable(t *testing.T) {
+	tests := []struct {
+		name      string
+		oldKey    string
+		newKey    string
+		overwrite bool
+		wantErr   error
+		want      map[string]string
+	}{
+		{"rename", "A", "X", false, nil, map[string]string{"X": "a", "B": "b"}},
+		{"taken", "A", "B", false, ErrAlreadyExists, map[string]string{"A": "a", "B": "b"}},
+		{"overwrite", "A", "B", true, nil, map[string]string{"B": "a"}},
+		{"missing", "Z", "X", false, ErrVariableNotFound, map[string]string{"A": "a", "B": "b"}},
+		{"missing overwrite", "Z", "B", true, ErrVariableNotFound, map[string]string{"A": "a", "B": "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testRepository(t)
+			project := testProject(t, r)
+			env := testEnvironment(t, r, project)
+			for key, value := range map[string]string{"A": "a", "B": "b"} {
+				if _, err := r.SetEnvVariable(project.ID, env.ID, key, value); err != nil {
+					t.Fatalf("setting %s: %v", key, err)
+				}
+			}
+
+			err := r.RenameEnvVariable(project.ID, env.ID, tt.oldKey, tt.newKey, tt.overwrite)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RenameEnvVariable() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := envValues(t, r, project.ID, env.ID); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("variables = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// benchmarkVariables returns n variables with distinct keys for environmentID
+func benchmarkVariables(environmentID uuid.UUID, n int) []EnvVariable {
+	variables := make([]EnvVariable, n)
+	for i := range variables {
+		position := i
+		variables[i] = EnvVariable{
+			EnvironmentID: environmentID,
+			Key:           fmt.Sprintf("KEY_%05d", i),
+			Value:         fmt.Sprintf("value-%d", i),
+			Position:      &position,
+		}
+	}
+	return variables
+}
+
+// BenchmarkSetEnvVariables compares an import in one transaction with parallel imports
+// of 2 and 4 batches, storing 1000 new variables per iteration. Parallel imports only
+// win when the round trips to the database dominate, so compare them against the
+// database the imports will use, e.g. a remote one, not only a local one:
+//
+//	GO_CLI_TEST_DB=... go test ./internal/app/models -run '^$' -bench SetEnvVariables
+func BenchmarkSetEnvVariables(b *testing.B) {
+	r := testRepository(b)
+	project := testProject(b, r)
+
+	for _, workers := range []int{1, 2, 4} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				env, err := r.CreateEnvironment(&project.ID, fmt.Sprintf("bench-%d-%d", workers, i), "")
+				if err != nil {
+					b.Fatalf("creating environment: %v", err)
+				}
+				variables := benchmarkVariables(env.ID, 1000)
+				b.StartTimer()
+
+				if _, err := r.SetEnvVariablesParallel(project.ID, variables, workers, nil); err != nil {
+					b.Fatalf("SetEnvVariablesParallel() error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteEnvVariablesByPattern(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		wantCount int64
+		wantKept  []string
+	}{
+		{"prefix", `OLD\_%`, 2, []string{"OLDER", "NEW_HOST", "100%"}},
+		{"ignores case", `old\_host`, 1, []string{"OLD_PORT", "OLDER", "NEW_HOST", "100%"}},
+		{"escaped percent", `100\%`, 1, []string{"OLD_HOST", "OLD_PORT", "OLDER", "NEW_HOST"}},
+		{"no match", `NONE%`, 0, []string{"OLD_HOST", "OLD_PORT", "OLDER", "NEW_HOST", "100%"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testRepository(t)
+			project := testProject(t, r)
+			env := testEnvironment(t, r, project)
+			for _, key := range []string{"OLD_HOST", "OLD_PORT", "OLDER", "NEW_HOST", "100%"} {
+				if _, err := r.SetEnvVariable(project.ID, env.ID, key, "x"); err != nil {
+					t.Fatalf("setting %s: %v", key, err)
+				}
+			}
+
+			count, err := r.DeleteEnvVariablesByPattern(project.ID, env.ID, tt.pattern)
+			if err != nil {
+				t.Fatalf("DeleteEnvVariablesByPattern() error = %v", err)
+			}
+			if count != tt.wantCount {
+				t.Errorf("DeleteEnvVariablesByPattern() = %d, want %d", count, tt.wantCount)
+			}
+
+			values := envValues(t, r, project.ID, env.ID)
+			if len(values) != len(tt.wantKept) {
+				t.Errorf("kept %v, want %v", values, tt.wantKept)
+			}
+			for _, key := range tt.wantKept {
+				if _, ok := values[key]; !ok {
+					t.Errorf("%s was deleted", key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateSnapshot copies every active variable of a project environment into a new
+// snapshot in a single transaction
+func (r *Repository) CreateSnapshot(projectID, environmentID uuid.UUID, description string) (snapshot *Snapshot, err error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	snapshot = &Snapshot{}
+	query := `
+		INSERT INTO env_snapshots (project_id, environment_id, description, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, project_id, environment_id, COALESCE(description, '') AS description, created_at
+	`
+	if err = tx.QueryRowx(query, projectID, environmentID, description, time.Now()).StructScan(snapshot); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot: %w", err)
+	}
+
+	copyQuery := `
+		INSERT INTO env_snapshot_variables (snapshot_id, key, value)
+		SELECT $1, key, value
+		FROM env_variables
+		WHERE project_id = $2 AND environment_id = $3 AND deleted_at IS NULL
+	`
+	result, err := tx.Exec(copyQuery, snapshot.ID, projectID, environmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy environment variables: %w", err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	snapshot.Variables = int(count)
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// GetSnapshots gets the snapshots of a project environment, newest first
+func (r *Repository) GetSnapshots(projectID, environmentID uuid.UUID) ([]Snapshot, error) {
+	snapshots := []Snapshot{}
+	query := `
+		SELECT s.id, s.project_id, s.environment_id, COALESCE(s.description, '') AS description,
+			(SELECT COUNT(*) FROM env_snapshot_variables v WHERE v.snapshot_id = s.id) AS variables,
+			s.created_at
+		FROM env_snapshots s
+		WHERE s.project_id = $1 AND s.environment_id = $2
+		ORDER BY s.id DESC
+	`
+
+	err := r.db.Select(&snapshots, query, projectID, environmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshots: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// GetSnapshot gets a snapshot by ID
+func (r *Repository) GetSnapshot(id int64) (*Snapshot, error) {
+	snapshot := &Snapshot{}
+	query := `
+		SELECT s.id, s.project_id, s.environment_id, COALESCE(s.description, '') AS description,
+			(SELECT COUNT(*) FROM env_snapshot_variables v WHERE v.snapshot_id = s.id) AS variables,
+			s.created_at
+		FROM env_snapshots s
+		WHERE s.id = $1
+	`
+
+	err := r.db.Get(snapshot, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w with ID %d", ErrSnapshotNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// RestoreSnapshot makes the active variables of a snapshot's project environment match
+// the snapshot exactly in a single transaction: keys whose value differs or that are
+// missing are set, and keys not in the snapshot are deleted.
+func (r *Repository) RestoreSnapshot(id int64) (restore *SnapshotRestore, err error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	snapshot := &Snapshot{}
+	err = tx.Get(snapshot, `SELECT id, project_id, environment_id, created_at FROM env_snapshots WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w with ID %d", ErrSnapshotNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+
+	var saved []struct {
+		Key   string `db:"key"`
+		Value string `db:"value"`
+	}
+	err = tx.Select(&saved, `SELECT key, COALESCE(value, '') AS value FROM env_snapshot_variables WHERE snapshot_id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot variables: %w", err)
+	}
+
+	var current []EnvVariable
+	currentQuery := `
+		SELECT id, project_id, environment_id, key, value, created_at, updated_at, deleted_at
+		FROM env_variables
+		WHERE project_id = $1 AND environment_id = $2 AND deleted_at IS NULL
+	`
+	if err = tx.Select(&current, currentQuery, snapshot.ProjectID, snapshot.EnvironmentID); err != nil {
+		return nil, fmt.Errorf("failed to get environment variables: %w", err)
+	}
+
+	values := make(map[string]string, len(current))
+	for _, v := range current {
+		values[v.Key] = v.Value
+	}
+
+	restore = &SnapshotRestore{Set: []string{}, Deleted: []string{}}
+	wanted := make(map[string]bool, len(saved))
+	for _, v := range saved {
+		wanted[v.Key] = true
+		if value, ok := values[v.Key]; ok && value == v.Value {
+			continue
+		}
+		if _, err = setEnvVariable(tx, snapshot.ProjectID, snapshot.EnvironmentID, v.Key, v.Value); err != nil {
+			return nil, fmt.Errorf("failed to set %s: %w", v.Key, err)
+		}
+		restore.Set = append(restore.Set, v.Key)
+	}
+
+	for key := range values {
+		if wanted[key] {
+			continue
+		}
+		if err = deleteEnvVariable(tx, snapshot.ProjectID, snapshot.EnvironmentID, key); err != nil {
+			return nil, fmt.Errorf("failed to delete %s: %w", key, err)
+		}
+		restore.Deleted = append(restore.Deleted, key)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	sort.Strings(restore.Set)
+	sort.Strings(restore.Deleted)
+	return restore, nil
+}
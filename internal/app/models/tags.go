@@ -0,0 +1,54 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// AddProjectTags adds tags to a project. Tags the project already has are ignored.
+func (r *Repository) AddProjectTags(projectID uuid.UUID, tags []string) error {
+	query := `
+		INSERT INTO project_tags (project_id, tag, created_at)
+		SELECT $1, tag, $3
+		FROM UNNEST($2::text[]) AS tag
+		ON CONFLICT (project_id, tag) DO NOTHING
+	`
+
+	_, err := r.db.Exec(query, projectID, pq.Array(tags), time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to add project tags: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveProjectTags removes tags from a project. Tags the project doesn't have are ignored.
+func (r *Repository) RemoveProjectTags(projectID uuid.UUID, tags []string) error {
+	_, err := r.db.Exec(`DELETE FROM project_tags WHERE project_id = $1 AND tag = ANY($2)`, projectID, pq.Array(tags))
+	if err != nil {
+		return fmt.Errorf("failed to remove project tags: %w", err)
+	}
+
+	return nil
+}
+
+// GetProjectTags gets the tags of a project, sorted
+func (r *Repository) GetProjectTags(projectID uuid.UUID) ([]string, error) {
+	tags := []string{}
+	query := `
+		SELECT tag
+		FROM project_tags
+		WHERE project_id = $1
+		ORDER BY tag
+	`
+
+	err := r.db.Select(&tags, query, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project tags: %w", err)
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SetVariableType declares the value type of a project's variable, replacing any
+// previous declaration for the key
+func (r *Repository) SetVariableType(projectID uuid.UUID, key, valueType string) (*VariableType, error) {
+	now := time.Now()
+	variableType := &VariableType{}
+	query := `
+		INSERT INTO variable_types (id, project_id, key, type, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (project_id, key) DO UPDATE SET type = EXCLUDED.type, updated_at = EXCLUDED.updated_at
+		RETURNING id, project_id, key, type, required, created_at, updated_at
+	`
+
+	err := r.db.QueryRowx(query, uuid.New(), projectID, key, valueType, now).StructScan(variableType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set variable type: %w", err)
+	}
+
+	return variableType, nil
+}
+
+// SetVariableRequired marks a project's variable as required or optional. A variable
+// without a declared type is declared as a string.
+func (r *Repository) SetVariableRequired(projectID uuid.UUID, key string, required bool) (*VariableType, error) {
+	now := time.Now()
+	variableType := &VariableType{}
+	query := `
+		INSERT INTO variable_types (id, project_id, key, type, required, created_at, updated_at)
+		VALUES ($1, $2, $3, 'string', $4, $5, $5)
+		ON CONFLICT (project_id, key) DO UPDATE SET required = EXCLUDED.required, updated_at = EXCLUDED.updated_at
+		RETURNING id, project_id, key, type, required, created_at, updated_at
+	`
+
+	err := r.db.QueryRowx(query, uuid.New(), projectID, key, required, now).StructScan(variableType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set variable required: %w", err)
+	}
+
+	return variableType, nil
+}
+
+// GetRequiredKeys gets the keys of the variables a project requires in every environment
+func (r *Repository) GetRequiredKeys(projectID uuid.UUID) ([]string, error) {
+	keys := []string{}
+	query := `
+		SELECT key
+		FROM variable_types
+		WHERE project_id = $1 AND required
+		ORDER BY key
+	`
+
+	err := r.db.Select(&keys, query, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get required keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// GetVariableTypes gets the declared value types of a project's variables
+func (r *Repository) GetVariableTypes(projectID uuid.UUID) ([]VariableType, error) {
+	variableTypes := []VariableType{}
+	query := `
+		SELECT id, project_id, key, type, required, created_at, updated_at
+		FROM variable_types
+		WHERE project_id = $1
+		ORDER BY key
+	`
+
+	err := r.db.Select(&variableTypes, query, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variable types: %w", err)
+	}
+
+	return variableTypes, nil
+}
+
+// DeleteVariableType removes the value type declared for a project's variable
+func (r *Repository) DeleteVariableType(projectID uuid.UUID, key string) error {
+	result, err := r.db.Exec(`DELETE FROM variable_types WHERE project_id = $1 AND key = $2`, projectID, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete variable type: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("no type declared for %s", key)
+	}
+
+	return nil
+}
@@ -3,12 +3,13 @@ package db
 import (
 	"fmt"
 	"io/fs"
-	"log"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
+	"go-env-cli/internal/pkg/logger"
+
 	"github.com/jmoiron/sqlx"
 )
 
@@ -44,6 +45,24 @@ func NewMigrationManager(db *sqlx.DB, migrationsFolder string) (*MigrationManage
 	}, nil
 }
 
+// FindMigrationsDir returns the first of the usual migration directory locations
+// that exists, or an empty string when none does
+func FindMigrationsDir() string {
+	possiblePaths := []string{
+		filepath.Join(".", "db", "migrations"),
+		filepath.Join("..", "..", "db", "migrations"),
+		filepath.Join(os.Getenv("HOME"), "go-env-cli", "db", "migrations"),
+	}
+
+	for _, path := range possiblePaths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	return ""
+}
+
 // MigrateUp executes all migration files
 func (m *MigrationManager) MigrateUp() error {
 	// Create migrations table if it doesn't exist
@@ -58,30 +77,20 @@ func (m *MigrationManager) MigrateUp() error {
 	}
 
 	// Check which migrations have been applied
-	appliedMigrations := make(map[string]bool)
-	rows, err := m.db.Query("SELECT version FROM schema_migrations")
+	appliedMigrations, err := m.appliedMigrations()
 	if err != nil {
-		return fmt.Errorf("error querying applied migrations: %w", err)
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var version string
-		if err := rows.Scan(&version); err != nil {
-			return fmt.Errorf("error scanning migration version: %w", err)
-		}
-		appliedMigrations[version] = true
+		return err
 	}
 
 	// Apply each migration
 	for _, migrationPath := range m.migrations {
 		version := filepath.Base(migrationPath)
 		if appliedMigrations[version] {
-			log.Printf("Migration %s already applied, skipping", version)
+			logger.Debugf("Migration %s already applied, skipping", version)
 			continue
 		}
 
-		log.Printf("Applying migration: %s", version)
+		logger.Infof("Applying migration: %s", version)
 
 		// Read migration content
 		content, err := os.ReadFile(migrationPath)
@@ -109,8 +118,91 @@ func (m *MigrationManager) MigrateUp() error {
 			return fmt.Errorf("error committing migration %s: %w", version, err)
 		}
 
-		log.Printf("Successfully applied migration: %s", version)
+		logger.Infof("Successfully applied migration: %s", version)
 	}
 
 	return nil
 }
+
+// PendingMigrations returns the versions of the migration files that have not been
+// applied yet. A database without the migrations table has every migration pending.
+func (m *MigrationManager) PendingMigrations() ([]string, error) {
+	var exists bool
+	if err := m.db.Get(&exists, "SELECT to_regclass('schema_migrations') IS NOT NULL"); err != nil {
+		return nil, fmt.Errorf("error checking migrations table: %w", err)
+	}
+
+	appliedMigrations := make(map[string]bool)
+	if exists {
+		var err error
+		appliedMigrations, err = m.appliedMigrations()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var pending []string
+	for _, migrationPath := range m.migrations {
+		if version := filepath.Base(migrationPath); !appliedMigrations[version] {
+			pending = append(pending, version)
+		}
+	}
+
+	return pending, nil
+}
+
+// appliedMigrations returns the set of migration versions recorded as applied
+func (m *MigrationManager) appliedMigrations() (map[string]bool, error) {
+	var versions []string
+	if err := m.db.Select(&versions, "SELECT version FROM schema_migrations"); err != nil {
+		return nil, fmt.Errorf("error querying applied migrations: %w", err)
+	}
+
+	applied := make(map[string]bool, len(versions))
+	for _, version := range versions {
+		applied[version] = true
+	}
+
+	return applied, nil
+}
+
+// ExpectedIndexes are the indexes created by the migrations that the common lookups rely on
+var ExpectedIndexes = []string{
+	"idx_env_variables_project_environment_key",
+	"idx_projects_active_name",
+	"idx_environments_project_name_lower",
+	"idx_environments_project",
+}
+
+// MissingIndexes returns the indexes among names that do not exist in the database
+func MissingIndexes(db *sqlx.DB, names ...string) ([]string, error) {
+	var missing []string
+	for _, name := range names {
+		var exists bool
+		query := `SELECT EXISTS (SELECT 1 FROM pg_indexes WHERE schemaname = current_schema() AND indexname = $1)`
+		if err := db.Get(&exists, query, name); err != nil {
+			return nil, fmt.Errorf("error checking index %s: %w", name, err)
+		}
+		if !exists {
+			missing = append(missing, name)
+		}
+	}
+
+	return missing, nil
+}
+
+// MissingTables returns the tables among names that do not exist in the database
+func MissingTables(db *sqlx.DB, names ...string) ([]string, error) {
+	var missing []string
+	for _, name := range names {
+		var exists bool
+		if err := db.Get(&exists, "SELECT to_regclass($1) IS NOT NULL", name); err != nil {
+			return nil, fmt.Errorf("error checking table %s: %w", name, err)
+		}
+		if !exists {
+			missing = append(missing, name)
+		}
+	}
+
+	return missing, nil
+}
@@ -1,35 +1,133 @@
 package db
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"net/url"
+	"os"
+	"strings"
 	"time"
 
+	"go-env-cli/internal/pkg/logger"
+
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+)
+
+// Connection pool defaults, kept small since each CLI invocation needs few connections
+const (
+	DefaultMaxOpenConns    = 5
+	DefaultMaxIdleConns    = 2
+	DefaultConnMaxLifetime = 5 * time.Minute
 )
 
+// ErrConnection is wrapped by the errors NewDB returns when the database can't be reached
+var ErrConnection = errors.New("failed to connect to database")
+
 type Config struct {
 	GO_CLI_DB string `mapstructure:"go_cli_db"`
+
+	// Optional TLS files, added to the connection string when set
+	SSLRootCert string `mapstructure:"sslrootcert"`
+	SSLCert     string `mapstructure:"sslcert"`
+	SSLKey      string `mapstructure:"sslkey"`
+
+	// Connection pool limits, the defaults are used when zero
+	MaxOpenConns    int           `mapstructure:"max_open_conns"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+
+	// DebugSQL logs every query with its duration. Queries slower than
+	// SlowQueryThreshold are logged as warnings either way; zero disables the warnings.
+	DebugSQL           bool          `mapstructure:"debug_sql"`
+	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold"`
 }
 
 // NewDB creates a new database connection
 func NewDB(config Config) (*sqlx.DB, error) {
-	db, err := sqlx.Connect("postgres", config.GO_CLI_DB)
+	dsn, err := config.dsn()
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, err
 	}
 
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConnection, err)
+	}
+
+	// Time every query through the connector
+	db := sqlx.NewDb(sql.OpenDB(tracingConnector{
+		connector: connector,
+		tracer:    tracer{logQueries: config.DebugSQL, slow: config.SlowQueryThreshold},
+	}), "postgres")
+
 	// Configure connection pool
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	db.SetMaxOpenConns(orDefault(config.MaxOpenConns, DefaultMaxOpenConns))
+	db.SetMaxIdleConns(orDefault(config.MaxIdleConns, DefaultMaxIdleConns))
+	db.SetConnMaxLifetime(orDefault(config.ConnMaxLifetime, DefaultConnMaxLifetime))
 
 	// Test the connection
 	if err := db.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+		db.Close()
+		return nil, fmt.Errorf("%w: ping failed: %w", ErrConnection, err)
 	}
 
-	log.Println("Successfully connected to database")
+	logger.Debugf("Successfully connected to database")
 	return db, nil
 }
+
+// dsn returns the connection string with the TLS file parameters added, after
+// checking that the files exist
+func (c Config) dsn() (string, error) {
+	params := [][2]string{
+		{"sslrootcert", c.SSLRootCert},
+		{"sslcert", c.SSLCert},
+		{"sslkey", c.SSLKey},
+	}
+
+	dsn := c.GO_CLI_DB
+	for _, param := range params {
+		name, path := param[0], param[1]
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("invalid %s: %w", name, err)
+		}
+
+		var err error
+		dsn, err = addDSNParam(dsn, name, path)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return dsn, nil
+}
+
+// addDSNParam sets a parameter in either a postgres:// URL or a key=value connection string
+func addDSNParam(dsn, name, value string) (string, error) {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return "", fmt.Errorf("invalid connection URL: %w", err)
+		}
+		q := u.Query()
+		q.Set(name, value)
+		u.RawQuery = q.Encode()
+		return u.String(), nil
+	}
+
+	escaped := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+	return strings.TrimSpace(dsn + " " + name + "='" + escaped + "'"), nil
+}
+
+// orDefault returns value, or def when value is zero
+func orDefault[T comparable](value, def T) T {
+	var zero T
+	if value == zero {
+		return def
+	}
+	return value
+}
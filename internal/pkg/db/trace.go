@@ -0,0 +1,166 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"time"
+
+	"go-env-cli/internal/pkg/logger"
+
+	"github.com/lib/pq"
+)
+
+// DefaultSlowQueryThreshold is how long a query may take before a warning is logged
+const DefaultSlowQueryThreshold = time.Second
+
+// tracer logs every query with its duration when enabled, and warns about queries that
+// take longer than the slow threshold
+type tracer struct {
+	logQueries bool
+	slow       time.Duration // no warnings when zero
+}
+
+// observe logs a query that started at start
+func (t tracer) observe(query string, start time.Time) {
+	elapsed := time.Since(start)
+	if t.logQueries {
+		logger.Infof("SQL (%s): %s", elapsed.Round(time.Microsecond), compactQuery(query))
+	}
+	if t.slow > 0 && elapsed > t.slow {
+		logger.Warnf("Slow query took %s (threshold %s): %s", elapsed.Round(time.Millisecond), t.slow, compactQuery(query))
+	}
+}
+
+// compactQuery collapses the whitespace of a query onto a single line
+func compactQuery(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// tracingConnector opens PostgreSQL connections whose queries are observed by a tracer
+type tracingConnector struct {
+	connector *pq.Connector
+	tracer    tracer
+}
+
+// Connect opens a traced connection
+func (c tracingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &tracingConn{Conn: conn, tracer: c.tracer}, nil
+}
+
+// Driver returns the PostgreSQL driver
+func (c tracingConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+// tracingConn times the queries, statements and transactions of a connection. The
+// optional driver interfaces are passed through to the wrapped connection.
+type tracingConn struct {
+	driver.Conn
+	tracer tracer
+}
+
+// QueryContext runs a query, timing it
+func (c *tracingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	defer c.tracer.observe(query, time.Now())
+	return queryer.QueryContext(ctx, query, args)
+}
+
+// ExecContext runs a statement, timing it
+func (c *tracingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	defer c.tracer.observe(query, time.Now())
+	return execer.ExecContext(ctx, query, args)
+}
+
+// PrepareContext prepares a statement whose executions are timed
+func (c *tracingConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tracingStmt{Stmt: stmt, query: query, tracer: c.tracer}, nil
+}
+
+// BeginTx starts a transaction
+func (c *tracingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+// Ping checks that the connection is alive
+func (c *tracingConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// ResetSession resets the connection before it is reused
+func (c *tracingConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+// IsValid reports whether the connection can be reused
+func (c *tracingConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// tracingStmt times the executions of a prepared statement
+type tracingStmt struct {
+	driver.Stmt
+	query  string
+	tracer tracer
+}
+
+// QueryContext runs the statement as a query, timing it
+func (s *tracingStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	defer s.tracer.observe(s.query, time.Now())
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		return queryer.QueryContext(ctx, args)
+	}
+	return s.Stmt.Query(namedValues(args))
+}
+
+// ExecContext runs the statement, timing it
+func (s *tracingStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	defer s.tracer.observe(s.query, time.Now())
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		return execer.ExecContext(ctx, args)
+	}
+	return s.Stmt.Exec(namedValues(args))
+}
+
+// namedValues drops the names of positional arguments
+func namedValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return values
+}
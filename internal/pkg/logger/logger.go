@@ -0,0 +1,101 @@
+// Package logger provides a minimal leveled logger for diagnostics. Messages go to
+// standard error so they never mix with command output on standard output.
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Level is the severity of a log message
+type Level int
+
+// Log levels, from most to least verbose
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// EnvVar is the environment variable read for the log level when no flag is given
+const EnvVar = "GO_ENV_CLI_LOG_LEVEL"
+
+var levelNames = map[Level]string{
+	LevelDebug: "DEBUG",
+	LevelInfo:  "INFO",
+	LevelWarn:  "WARN",
+	LevelError: "ERROR",
+}
+
+var (
+	mu    sync.Mutex
+	level           = LevelInfo
+	out   io.Writer = os.Stderr
+)
+
+// String returns the lowercase name of the level
+func (l Level) String() string {
+	return strings.ToLower(levelNames[l])
+}
+
+// ParseLevel parses a level name: debug, info, warn (or warning) or error
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("invalid log level '%s' (expected debug, info, warn or error)", s)
+}
+
+// SetLevel sets the minimum level of the messages that are written
+func SetLevel(l Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	level = l
+}
+
+// SetOutput sets where messages are written, standard error by default
+func SetOutput(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	out = w
+}
+
+// Debugf logs a debug message
+func Debugf(format string, args ...interface{}) {
+	logf(LevelDebug, format, args...)
+}
+
+// Infof logs an informational message
+func Infof(format string, args ...interface{}) {
+	logf(LevelInfo, format, args...)
+}
+
+// Warnf logs a warning
+func Warnf(format string, args ...interface{}) {
+	logf(LevelWarn, format, args...)
+}
+
+// Errorf logs an error
+func Errorf(format string, args ...interface{}) {
+	logf(LevelError, format, args...)
+}
+
+func logf(l Level, format string, args ...interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+	if l < level {
+		return
+	}
+	fmt.Fprintf(out, "[%s] %s\n", levelNames[l], fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ChecksumExtension is appended to the name of a file to name its checksum file
+const ChecksumExtension = ".sha256"
+
+// ErrChecksumMismatch is returned when a file doesn't match its recorded checksum
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// FileSHA256 returns the hex-encoded SHA-256 digest of the contents of a file
+func FileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// WriteChecksumFile records the SHA-256 digest of a file in <path>.sha256, in the
+// format of sha256sum so "sha256sum -c" can check it too. It returns the digest.
+func WriteChecksumFile(path string) (string, error) {
+	digest, err := FileSHA256(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute checksum: %w", err)
+	}
+
+	line := fmt.Sprintf("%s  %s\n", digest, filepath.Base(path))
+	if err := os.WriteFile(path+ChecksumExtension, []byte(line), 0644); err != nil {
+		return "", fmt.Errorf("failed to write checksum file: %w", err)
+	}
+
+	return digest, nil
+}
+
+// VerifyChecksumFile compares a file with the digest recorded in <path>.sha256,
+// returning an error matching ErrChecksumMismatch when they differ
+func VerifyChecksumFile(path string) error {
+	content, err := os.ReadFile(path + ChecksumExtension)
+	if err != nil {
+		return fmt.Errorf("failed to read checksum file: %w", err)
+	}
+
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return fmt.Errorf("checksum file %s is empty", path+ChecksumExtension)
+	}
+	expected := strings.ToLower(fields[0])
+
+	actual, err := FileSHA256(path)
+	if err != nil {
+		return fmt.Errorf("failed to compute checksum: %w", err)
+	}
+
+	if actual != expected {
+		return fmt.Errorf("%w: %s has SHA-256 %s, expected %s", ErrChecksumMismatch, path, actual, expected)
+	}
+
+	return nil
+}
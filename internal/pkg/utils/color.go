@@ -0,0 +1,74 @@
+package utils
+
+import "os"
+
+// NoColorEnvVar disables colored output when set to a non-empty value (see no-color.org)
+const NoColorEnvVar = "NO_COLOR"
+
+// ANSI escape sequences used by Colorizer
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// Colorizer wraps text in ANSI colors when Enabled, and returns it unchanged
+// otherwise. The zero value doesn't color anything.
+type Colorizer struct {
+	Enabled bool
+}
+
+// Key colors a variable key
+func (c Colorizer) Key(s string) string {
+	return c.wrap(ansiCyan, s)
+}
+
+// Value colors a variable value
+func (c Colorizer) Value(s string) string {
+	return c.wrap(ansiYellow, s)
+}
+
+// Added colors a line describing something added
+func (c Colorizer) Added(s string) string {
+	return c.wrap(ansiGreen, s)
+}
+
+// Removed colors a line describing something removed
+func (c Colorizer) Removed(s string) string {
+	return c.wrap(ansiRed, s)
+}
+
+// Changed colors a line describing something changed
+func (c Colorizer) Changed(s string) string {
+	return c.wrap(ansiYellow, s)
+}
+
+// Heading makes a heading stand out
+func (c Colorizer) Heading(s string) string {
+	return c.wrap(ansiBold, s)
+}
+
+func (c Colorizer) wrap(code, s string) string {
+	if !c.Enabled || s == "" {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// UseColor decides whether output should be colored: not when noColor is set, when
+// the value of NO_COLOR isn't empty, or when the output isn't a terminal
+func UseColor(noColor bool, noColorEnv string, terminal bool) bool {
+	return !noColor && noColorEnv == "" && terminal
+}
+
+// IsTerminal reports whether f is a terminal rather than a file or a pipe
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUseColor(t *testing.T) {
+	tests := []struct {
+		name       string
+		noColor    bool
+		noColorEnv string
+		terminal   bool
+		want       bool
+	}{
+		{"terminal", false, "", true, true},
+		{"pipe", false, "", false, false},
+		{"--no-color", true, "", true, false},
+		{"NO_COLOR", false, "1", true, false},
+		{"NO_COLOR empty", false, "", true, true},
+		{"everything off", true, "1", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UseColor(tt.noColor, tt.noColorEnv, tt.terminal); got != tt.want {
+				t.Errorf("UseColor(%v, %q, %v) = %v, want %v", tt.noColor, tt.noColorEnv, tt.terminal, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorizer(t *testing.T) {
+	on := Colorizer{Enabled: true}
+	off := Colorizer{}
+
+	tests := []struct {
+		name  string
+		color func(Colorizer, string) string
+		code  string
+	}{
+		{"key", Colorizer.Key, ansiCyan},
+		{"value", Colorizer.Value, ansiYellow},
+		{"added", Colorizer.Added, ansiGreen},
+		{"removed", Colorizer.Removed, ansiRed},
+		{"changed", Colorizer.Changed, ansiYellow},
+		{"heading", Colorizer.Heading, ansiBold},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := tt.color(on, "text"), tt.code+"text"+ansiReset; got != want {
+				t.Errorf("enabled = %q, want %q", got, want)
+			}
+			if got := tt.color(off, "text"); got != "text" {
+				t.Errorf("disabled = %q, want the text unchanged", got)
+			}
+			if got := tt.color(on, ""); got != "" {
+				t.Errorf("enabled on empty text = %q, want it empty", got)
+			}
+		})
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if IsTerminal(f) {
+		t.Error("IsTerminal() = true for a regular file")
+	}
+}
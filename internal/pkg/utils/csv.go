@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseCSV parses variables from CSV as described in RFC 4180. The first row is a
+// header naming a key and a value column, and optionally an environment column, in
+// any order and case. Other columns are ignored.
+func ParseCSV(r io.Reader) ([]EnvEntry, error) {
+	reader := csv.NewReader(r)
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV header: %w", err)
+	}
+
+	columns := map[string]int{"key": -1, "value": -1, "environment": -1}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := columns[name]; ok {
+			columns[name] = i
+		}
+	}
+	if columns["key"] < 0 || columns["value"] < 0 {
+		return nil, fmt.Errorf("CSV header must have key and value columns, got: %s", strings.Join(header, ","))
+	}
+
+	var entries []EnvEntry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		key := strings.TrimSpace(record[columns["key"]])
+		if key == "" {
+			return nil, fmt.Errorf("invalid format at line %d: empty key", line)
+		}
+
+		entry := EnvEntry{Key: key, Value: record[columns["value"]], Line: line}
+		if i := columns["environment"]; i >= 0 {
+			entry.Environment = strings.TrimSpace(record[i])
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
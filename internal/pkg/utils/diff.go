@@ -0,0 +1,58 @@
+package utils
+
+import "sort"
+
+// EnvChange describes a single key whose value differs between two sets of variables
+type EnvChange struct {
+	Key      string
+	OldValue string
+	NewValue string
+}
+
+// EnvDiff describes how to turn one set of variables into another
+type EnvDiff struct {
+	Added   []EnvChange
+	Changed []EnvChange
+	Removed []EnvChange
+}
+
+// Empty reports whether the diff contains no changes
+func (d EnvDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Changed) == 0 && len(d.Removed) == 0
+}
+
+// DiffEnv compares the current variables with the desired ones. Keys only in desired
+// are added, keys only in current are removed and keys in both with different values
+// are changed. Each list is sorted by key.
+func DiffEnv(current, desired map[string]string) EnvDiff {
+	var diff EnvDiff
+
+	for key, newValue := range desired {
+		oldValue, ok := current[key]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, EnvChange{Key: key, NewValue: newValue})
+		case oldValue != newValue:
+			diff.Changed = append(diff.Changed, EnvChange{Key: key, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+
+	for key, oldValue := range current {
+		if _, ok := desired[key]; !ok {
+			diff.Removed = append(diff.Removed, EnvChange{Key: key, OldValue: oldValue})
+		}
+	}
+
+	sortChanges(diff.Added)
+	sortChanges(diff.Changed)
+	sortChanges(diff.Removed)
+
+	return diff
+}
+
+// sortChanges sorts changes by key
+func sortChanges(changes []EnvChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+}
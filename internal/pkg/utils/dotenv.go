@@ -0,0 +1,295 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxLineSize is the largest single logical line ParseEnv accepts
+const maxLineSize = 1024 * 1024
+
+// utf8BOM is the byte order mark some Windows tools write at the start of UTF-8 files
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// NormalizeNewlines strips a leading UTF-8 byte order mark from content and turns
+// Windows (\r\n) and old Mac (\r) line endings into \n, so files written on Windows
+// don't leave a BOM on the first key and a \r on every value
+func NormalizeNewlines(content []byte) []byte {
+	content = bytes.TrimPrefix(content, utf8BOM)
+	if bytes.IndexByte(content, '\r') < 0 {
+		return content
+	}
+
+	content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	return bytes.ReplaceAll(content, []byte("\r"), []byte("\n"))
+}
+
+// EnvEntry is a single variable parsed from a .env file. Environment is only set
+// by formats that name the environment of each variable, such as CSV.
+type EnvEntry struct {
+	Key         string
+	Value       string
+	Line        int
+	Environment string
+}
+
+// ParseOptions controls how ParseEnvWith reads a .env file
+type ParseOptions struct {
+	// NoTrim keeps the whitespace around unquoted values, such as intentional trailing
+	// spaces, instead of trimming it. Keys are trimmed either way.
+	NoTrim bool
+}
+
+// ParseEnv parses the contents of a .env file. Empty lines and lines starting with
+// "#" or "//" are skipped. A quoted value whose closing quote is not on the same line
+// is continued on the following lines, so multiline values such as PEM keys survive.
+func ParseEnv(r io.Reader) ([]EnvEntry, error) {
+	return ParseEnvWith(r, ParseOptions{})
+}
+
+// ParseEnvWith parses the contents of a .env file like ParseEnv, as controlled by opts
+func ParseEnvWith(r io.Reader, opts ParseOptions) ([]EnvEntry, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var entries []EnvEntry
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+
+		// Skip empty lines and comments
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		// Keep the trailing whitespace of the value
+		if opts.NoTrim {
+			line = strings.TrimLeft(raw, " \t")
+		}
+
+		// Reassemble values whose opening quote is closed on a later line
+		startLine := lineNumber
+		if hasUnclosedQuote(line) {
+			line = strings.TrimLeft(raw, " \t")
+			for hasUnclosedQuote(line) {
+				if !scanner.Scan() {
+					return nil, fmt.Errorf("unterminated quoted value starting at line %d", startLine)
+				}
+				lineNumber++
+				line += "\n" + scanner.Text()
+			}
+		}
+
+		key, value, err := parseKeyValuePair(line, opts.NoTrim)
+		if err != nil {
+			return nil, fmt.Errorf("invalid format at line %d: %w", startLine, err)
+		}
+
+		entries = append(entries, EnvEntry{Key: key, Value: value, Line: startLine})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading env file: %w", err)
+	}
+
+	return entries, nil
+}
+
+// FormatEnvValue renders a value so that ParseKeyValuePair reads it back unchanged.
+// Values that would otherwise be altered by parsing are wrapped in double quotes with
+// backslashes, quotes and carriage returns escaped; newlines are kept literally so
+// multiline values stay readable.
+func FormatEnvValue(value string) string {
+	if !needsQuoting(value) {
+		return value
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\r", `\r`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// needsQuoting reports whether an unquoted value would not survive parsing as written
+func needsQuoting(value string) bool {
+	if value == "" {
+		return false
+	}
+	if strings.ContainsAny(value, "\n\r") {
+		return true
+	}
+	if value != strings.TrimSpace(value) {
+		return true
+	}
+	if strings.Contains(value, " #") || strings.Contains(value, "\t#") {
+		return true
+	}
+	return value[0] == '"' || value[0] == '\''
+}
+
+// hasUnclosedQuote reports whether the value of a KEY=value line opens a quote it does not close
+func hasUnclosedQuote(line string) bool {
+	idx := strings.Index(line, "=")
+	if idx < 0 {
+		return false
+	}
+
+	value := strings.TrimLeft(line[idx+1:], " \t")
+	if value == "" || (value[0] != '"' && value[0] != '\'') {
+		return false
+	}
+
+	return closingQuote(value, value[0]) < 0
+}
+
+// ParseKeyValuePair parses a single KEY=value line from a .env file.
+// A leading "export " before the key is ignored and inline comments are removed as
+// described by StripInlineComment. Values wrapped in matching single or double quotes have the quotes removed and
+// their inner whitespace preserved. Double-quoted values additionally have the
+// escape sequences \n, \t, \" and \\ expanded; single-quoted values are literal.
+func ParseKeyValuePair(line string) (string, string, error) {
+	return parseKeyValuePair(line, false)
+}
+
+// parseKeyValuePair parses a KEY=value line like ParseKeyValuePair, keeping the
+// whitespace around an unquoted value when noTrim is true
+func parseKeyValuePair(line string, noTrim bool) (string, string, error) {
+	parts := strings.SplitN(line, "=", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("missing '=' separator")
+	}
+
+	key := strings.TrimSpace(parts[0])
+
+	// Allow shell-sourceable files that prefix assignments with "export"
+	if fields := strings.Fields(key); len(fields) == 2 && fields[0] == "export" {
+		key = fields[1]
+	}
+
+	if key == "" {
+		return "", "", fmt.Errorf("empty key")
+	}
+
+	value, err := parseValue(parts[1], noTrim)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+
+	return key, value, nil
+}
+
+// StripInlineComment removes a trailing "# comment" from a raw value. A "#" only
+// starts a comment when it is preceded by whitespace and is not inside the quotes
+// of a quoted value, so "http://x#frag" and "\"a # b\"" are left untouched.
+func StripInlineComment(raw string) string {
+	value := strings.TrimLeft(raw, " \t")
+	offset := len(raw) - len(value)
+
+	// Skip over a leading quoted section, comments can only follow it
+	start := 0
+	if value != "" && (value[0] == '"' || value[0] == '\'') {
+		end := closingQuote(value, value[0])
+		if end < 0 {
+			return raw
+		}
+		start = end + 1
+	}
+
+	for i := start; i < len(value); i++ {
+		if value[i] != '#' {
+			continue
+		}
+		if pos := offset + i; pos > 0 && (raw[pos-1] == ' ' || raw[pos-1] == '\t') {
+			return strings.TrimRight(raw[:pos], " \t")
+		}
+	}
+
+	return raw
+}
+
+// parseValue resolves the quoting of a raw value. Whitespace around an unquoted value
+// is trimmed unless noTrim is true.
+func parseValue(raw string, noTrim bool) (string, error) {
+	stripped := StripInlineComment(raw)
+	value := strings.TrimLeft(stripped, " \t")
+	if value == "" {
+		if noTrim {
+			return stripped, nil
+		}
+		return "", nil
+	}
+
+	switch value[0] {
+	case '"':
+		end := closingQuote(value, '"')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated double-quoted value")
+		}
+		return unescapeDoubleQuoted(value[1:end]), nil
+	case '\'':
+		end := closingQuote(value, '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated single-quoted value")
+		}
+		return value[1:end], nil
+	}
+
+	if noTrim {
+		return stripped, nil
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// closingQuote returns the index of the quote closing the one at value[0], or -1.
+// Inside double quotes a backslash escapes the following character.
+func closingQuote(value string, quote byte) int {
+	for i := 1; i < len(value); i++ {
+		if quote == '"' && value[i] == '\\' {
+			i++
+			continue
+		}
+		if value[i] == quote {
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeDoubleQuoted expands the escape sequences supported inside double quotes
+func unescapeDoubleQuoted(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c != '\\' || i == len(value)-1 {
+			b.WriteByte(c)
+			continue
+		}
+
+		i++
+		switch value[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case '"':
+			b.WriteByte('"')
+		case '\\':
+			b.WriteByte('\\')
+		default:
+			// Unknown escape, keep it as written
+			b.WriteByte('\\')
+			b.WriteByte(value[i])
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,223 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseKeyValuePair(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		wantKey   string
+		wantValue string
+	}{
+		{"unquoted", "KEY=value", "KEY", "value"},
+		{"unquoted trimmed", "KEY =  value  ", "KEY", "value"},
+		{"empty", "KEY=", "KEY", ""},
+		{"equals in value", "URL=postgres://u:p@h/db?x=1", "URL", "postgres://u:p@h/db?x=1"},
+		{"single quoted is literal", "KEY='literal $X'", "KEY", "literal $X"},
+		{"single quoted keeps escapes", `KEY='a\nb'`, "KEY", `a\nb`},
+		{"double quoted keeps spaces", `KEY="  spaced  "`, "KEY", "  spaced  "},
+		{"double quoted newline", `KEY="line1\nline2"`, "KEY", "line1\nline2"},
+		{"double quoted tab", `KEY="a\tb"`, "KEY", "a\tb"},
+		{"double quoted quote", `KEY="say \"hi\""`, "KEY", `say "hi"`},
+		{"double quoted backslash", `KEY="C:\\dir"`, "KEY", `C:\dir`},
+		{"double quoted unknown escape", `KEY="a\qb"`, "KEY", `a\qb`},
+		{"unquoted comment", "KEY=unquoted # not-a-comment", "KEY", "unquoted"},
+		{"unquoted hash", "KEY=a#b", "KEY", "a#b"},
+		{"export prefix", "export FOO=bar", "FOO", "bar"},
+		{"export prefix with spaces", "export   FOO = bar", "FOO", "bar"},
+		{"export key", "export=1", "export", "1"},
+		{"export-like key", "exporter=1", "exporter", "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, value, err := ParseKeyValuePair(tt.line)
+			if err != nil {
+				t.Fatalf("ParseKeyValuePair(%q) error = %v", tt.line, err)
+			}
+			if key != tt.wantKey || value != tt.wantValue {
+				t.Errorf("ParseKeyValuePair(%q) = %q, %q, want %q, %q", tt.line, key, value, tt.wantKey, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestParseKeyValuePairErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"no separator", "KEY"},
+		{"empty key", "=value"},
+		{"unterminated double quote", `KEY="open`},
+		{"unterminated single quote", "KEY='open"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ParseKeyValuePair(tt.line); err == nil {
+				t.Errorf("ParseKeyValuePair(%q) succeeded, want an error", tt.line)
+			}
+		})
+	}
+}
+
+func TestFormatEnvValueRoundTrip(t *testing.T) {
+	values := []string{
+		"",
+		"plain",
+		"  spaced  ",
+		"line1\nline2",
+		`say "hi"`,
+		`C:\dir`,
+		"'single'",
+		"a # b",
+		"carriage\rreturn",
+	}
+	for _, value := range values {
+		t.Run(value, func(t *testing.T) {
+			_, got, err := ParseKeyValuePair("KEY=" + FormatEnvValue(value))
+			if err != nil {
+				t.Fatalf("parsing %q: %v", FormatEnvValue(value), err)
+			}
+			if got != value {
+				t.Errorf("round trip of %q = %q", value, got)
+			}
+		})
+	}
+}
+
+func TestParseEnvMultiline(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []EnvEntry
+	}{
+		{
+			"double quoted",
+			"A=1\nKEY=\"-----BEGIN KEY-----\nabc\n-----END KEY-----\"\nB=2\n",
+			[]EnvEntry{
+				{Key: "A", Value: "1", Line: 1},
+				{Key: "KEY", Value: "-----BEGIN KEY-----\nabc\n-----END KEY-----", Line: 2},
+				{Key: "B", Value: "2", Line: 5},
+			},
+		},
+		{
+			"single quoted",
+			"KEY='a\n  b'\n",
+			[]EnvEntry{{Key: "KEY", Value: "a\n  b", Line: 1}},
+		},
+		{
+			"escaped quote doesn't close",
+			"KEY=\"a \\\" b\nc\"\n",
+			[]EnvEntry{{Key: "KEY", Value: "a \" b\nc", Line: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseEnv(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ParseEnv() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseEnv() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEnvUnterminated(t *testing.T) {
+	_, err := ParseEnv(strings.NewReader("A=1\nKEY=\"never closed\nB=2\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("ParseEnv() error = %v, want an unterminated value at line 2", err)
+	}
+}
+
+func TestStripInlineComment(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"8080 # default", "8080"},
+		{"8080\t# default", "8080"},
+		{"8080", "8080"},
+		{"http://x#frag", "http://x#frag"},
+		{"a#b # c", "a#b"},
+		{`"a # b"`, `"a # b"`},
+		{`"a # b" # comment`, `"a # b"`},
+		{`'a # b' # comment`, `'a # b'`},
+		{`"a \" # b"`, `"a \" # b"`},
+		{`"unclosed # b`, `"unclosed # b`},
+		{"# only a comment", "# only a comment"},
+		{" # leading", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if got := StripInlineComment(tt.raw); got != tt.want {
+				t.Errorf("StripInlineComment(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEnvNoTrim(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		want   string
+		noTrim string
+	}{
+		{"spaces around value", "KEY=  value  ", "value", "  value  "},
+		{"spaces around key", "  KEY  =value", "value", "value"},
+		{"trailing tab", "KEY=value\t", "value", "value\t"},
+		{"double quoted", `KEY=  "  quoted  "  `, "  quoted  ", "  quoted  "},
+		{"single quoted", "KEY='  quoted  '", "  quoted  ", "  quoted  "},
+		{"comment", "KEY=  value  # comment", "value", "  value"},
+		{"only spaces", "KEY=   ", "", "   "},
+		{"export", "export KEY= value ", "value", " value "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, noTrim := range []bool{false, true} {
+				want := tt.want
+				if noTrim {
+					want = tt.noTrim
+				}
+
+				entries, err := ParseEnvWith(strings.NewReader(tt.line+"\n"), ParseOptions{NoTrim: noTrim})
+				if err != nil {
+					t.Fatalf("ParseEnvWith(NoTrim: %v) error = %v", noTrim, err)
+				}
+				if len(entries) != 1 || entries[0].Key != "KEY" || entries[0].Value != want {
+					t.Errorf("ParseEnvWith(%q, NoTrim: %v) = %+v, want KEY=%q", tt.line, noTrim, entries, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeNewlines(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unix", "A=1\nB=2\n", "A=1\nB=2\n"},
+		{"bom", "\xef\xbb\xbfA=1\n", "A=1\n"},
+		{"crlf", "A=1\r\nB=2\r\n", "A=1\nB=2\n"},
+		{"old mac", "A=1\rB=2\r", "A=1\nB=2\n"},
+		{"mixed", "\xef\xbb\xbfA=1\r\nB=2\rC=3\n", "A=1\nB=2\nC=3\n"},
+		{"bom only at the start", "A=\xef\xbb\xbf\n", "A=\xef\xbb\xbf\n"},
+		{"blank crlf lines", "\r\n\r\nA=1", "\n\nA=1"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(NormalizeNewlines([]byte(tt.content))); got != tt.want {
+				t.Errorf("NormalizeNewlines(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
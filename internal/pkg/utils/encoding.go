@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"encoding/base64"
+	"strings"
+	"unicode"
+)
+
+// EncodeBase64 encodes arbitrary bytes as standard, padded base64 so they can be
+// stored as a text value
+func EncodeBase64(data []byte) string {
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// DecodeBase64 decodes a value produced by EncodeBase64. Whitespace such as the line
+// breaks of wrapped base64 is ignored.
+func DecodeBase64(value string) ([]byte, error) {
+	value = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, value)
+
+	return base64.StdEncoding.DecodeString(value)
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"testing"
+)
+
+func TestBase64RoundTrip(t *testing.T) {
+	for _, size := range []int{0, 1, 2, 3, 64, 4096, 100003} {
+		t.Run(fmt.Sprint(size), func(t *testing.T) {
+			data := make([]byte, size)
+			if _, err := rand.Read(data); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := DecodeBase64(EncodeBase64(data))
+			if err != nil {
+				t.Fatalf("DecodeBase64() error = %v", err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("round trip of %d random bytes changed them", size)
+			}
+		})
+	}
+}
+
+func TestDecodeBase64(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"padded", "aGVsbG8=", "hello", false},
+		{"wrapped", "aGVs\nbG8g\r\nd29y bGQ=", "hello world", false},
+		{"missing padding", "aGVsbG8", "", true},
+		{"invalid character", "aGVs*G8=", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeBase64(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeBase64(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("DecodeBase64(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Encryption tools run to encrypt and decrypt files
+const (
+	ToolAge = "age"
+	ToolGPG = "gpg"
+)
+
+// ErrEncryptionToolMissing is returned when the age or gpg binary needed to encrypt or
+// decrypt isn't installed
+var ErrEncryptionToolMissing = errors.New("encryption tool not found")
+
+// EncryptionTool returns the tool that encrypts to recipient: age for age public keys
+// (age1...) and SSH public keys, gpg for anything else, such as an email address or
+// a key ID
+func EncryptionTool(recipient string) string {
+	if strings.HasPrefix(recipient, "age1") || strings.HasPrefix(recipient, "ssh-") {
+		return ToolAge
+	}
+	return ToolGPG
+}
+
+// DecryptionTool returns the tool that decrypts ciphertext: age for binary or armored
+// age files, gpg for anything else
+func DecryptionTool(ciphertext []byte) string {
+	if bytes.HasPrefix(ciphertext, []byte("age-encryption.org/")) ||
+		bytes.HasPrefix(ciphertext, []byte("-----BEGIN AGE ENCRYPTED FILE-----")) {
+		return ToolAge
+	}
+	return ToolGPG
+}
+
+// Encrypt encrypts plaintext to recipient with age or gpg, as chosen by
+// EncryptionTool
+func Encrypt(plaintext []byte, recipient string) ([]byte, error) {
+	tool := EncryptionTool(recipient)
+	if tool == ToolAge {
+		return runEncryptionTool(tool, plaintext, "--encrypt", "--recipient", recipient)
+	}
+	return runEncryptionTool(tool, plaintext, "--batch", "--quiet", "--encrypt", "--recipient", recipient, "--output", "-")
+}
+
+// Decrypt decrypts ciphertext written by age or gpg. age needs the file of an identity
+// the content was encrypted to; gpg looks the secret key up in its keyring and may ask
+// for its passphrase.
+func Decrypt(ciphertext []byte, identity string) ([]byte, error) {
+	if DecryptionTool(ciphertext) == ToolAge {
+		if identity == "" {
+			return nil, fmt.Errorf("decrypting an age file needs an identity file")
+		}
+		return runEncryptionTool(ToolAge, ciphertext, "--decrypt", "--identity", identity)
+	}
+	return runEncryptionTool(ToolGPG, ciphertext, "--quiet", "--decrypt", "--output", "-")
+}
+
+// runEncryptionTool runs tool with args, feeding it input and returning its output
+func runEncryptionTool(tool string, input []byte, args ...string) ([]byte, error) {
+	path, err := exec.LookPath(tool)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s is not installed or not in PATH", ErrEncryptionToolMissing, tool)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("%s failed: %s", tool, message)
+		}
+		return nil, fmt.Errorf("%s failed: %w", tool, err)
+	}
+
+	return stdout.Bytes(), nil
+}
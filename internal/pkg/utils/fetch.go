@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrResponseTooLarge is returned when a fetched body exceeds its size limit
+var ErrResponseTooLarge = errors.New("response too large")
+
+// IsURL reports whether s is an http:// or https:// URL rather than a file path
+func IsURL(s string) bool {
+	lower := strings.ToLower(s)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// DisplayURL returns rawURL without its credentials and query string, which may hold
+// tokens, so it can be shown or stored
+func DisplayURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
+
+// FetchURL requests rawURL with GET and returns its body. headers are "Name: value"
+// lines added to the request, e.g. "Authorization: Bearer ...". The whole request,
+// including reading the body, must finish within timeout, and reading more than
+// maxSize bytes fails with ErrResponseTooLarge. The caller closes the body.
+func FetchURL(rawURL string, headers []string, timeout time.Duration, maxSize int64) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	for _, header := range headers {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			// Don't repeat the header, it may hold a credential
+			return nil, fmt.Errorf("invalid header, expected \"Name: value\"")
+		}
+		req.Header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		// The error of the client repeats the full URL, query string included
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("failed to fetch %s: %w", DisplayURL(rawURL), err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch %s: %s", DisplayURL(rawURL), resp.Status)
+	}
+	if resp.ContentLength > maxSize {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s is %d bytes, the limit is %d", ErrResponseTooLarge, DisplayURL(rawURL), resp.ContentLength, maxSize)
+	}
+
+	return &limitedBody{body: resp.Body, remaining: maxSize, maxSize: maxSize}, nil
+}
+
+// limitedBody reads a response body, failing once it exceeds maxSize bytes instead of
+// silently truncating it like io.LimitReader
+type limitedBody struct {
+	body      io.ReadCloser
+	remaining int64
+	maxSize   int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, fmt.Errorf("%w: the limit is %d bytes", ErrResponseTooLarge, b.maxSize)
+	}
+	// Read one byte past the limit to tell a body of exactly maxSize bytes from a
+	// longer one
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.body.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n + int(b.remaining), fmt.Errorf("%w: the limit is %d bytes", ErrResponseTooLarge, b.maxSize)
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
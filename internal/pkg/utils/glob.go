@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Glob is a compiled glob-style pattern matched ignoring case. "*" matches any run of
+// characters, including none, and "?" matches a single character; every other
+// character matches itself. Compile a pattern once and reuse it to match many keys.
+type Glob struct {
+	re *regexp.Regexp
+}
+
+// CompileGlob compiles a glob-style pattern. Every pattern is valid.
+func CompileGlob(pattern string) *Glob {
+	var expr strings.Builder
+	expr.WriteString("(?is)^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+
+	return &Glob{re: regexp.MustCompile(expr.String())}
+}
+
+// Match reports whether s matches the pattern
+func (g *Glob) Match(s string) bool {
+	return g.re.MatchString(s)
+}
+
+// Globs is a set of compiled glob-style patterns
+type Globs []*Glob
+
+// CompileGlobs compiles several glob-style patterns
+func CompileGlobs(patterns []string) Globs {
+	globs := make(Globs, len(patterns))
+	for i, pattern := range patterns {
+		globs[i] = CompileGlob(pattern)
+	}
+	return globs
+}
+
+// MatchAny reports whether s matches any of the patterns
+func (gs Globs) MatchAny(s string) bool {
+	for _, g := range gs {
+		if g.Match(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// GlobToLike turns a glob-style pattern into a SQL LIKE pattern matching the same
+// strings, escaping the LIKE wildcards % and _ and the escape character \ so they
+// match themselves. Use it with ILIKE to ignore case like Glob does.
+func GlobToLike(pattern string) string {
+	var like strings.Builder
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			like.WriteByte('%')
+		case '?':
+			like.WriteByte('_')
+		case '%', '_', '\\':
+			like.WriteByte('\\')
+			like.WriteRune(r)
+		default:
+			like.WriteRune(r)
+		}
+	}
+	return like.String()
+}
@@ -0,0 +1,78 @@
+package utils
+
+import "testing"
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		want    bool
+	}{
+		{"*", "", true},
+		{"*", "ANY_KEY", true},
+		{"OLD_*", "OLD_HOST", true},
+		{"OLD_*", "old_host", true},
+		{"OLD_*", "OLD_", true},
+		{"OLD_*", "MY_OLD_HOST", false},
+		{"*_URL", "DATABASE_URL", true},
+		{"DB_?", "DB_1", true},
+		{"DB_?", "DB_12", false},
+		{"a.b", "a.b", true},
+		{"a.b", "axb", false},
+		{"[x]", "[x]", true},
+		{"100%", "100%", true},
+		{"LINE*", "LINE\nBREAK", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.s, func(t *testing.T) {
+			if got := CompileGlob(tt.pattern).Match(tt.s); got != tt.want {
+				t.Errorf("CompileGlob(%q).Match(%q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGlobsMatchAny(t *testing.T) {
+	globs := CompileGlobs([]string{"INTERNAL_*", "*_SECRET"})
+
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{"INTERNAL_TOKEN", true},
+		{"API_SECRET", true},
+		{"PUBLIC_URL", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if got := globs.MatchAny(tt.s); got != tt.want {
+				t.Errorf("MatchAny(%q) = %v, want %v", tt.s, got, tt.want)
+			}
+		})
+	}
+
+	if CompileGlobs(nil).MatchAny("KEY") {
+		t.Error("MatchAny() without patterns = true")
+	}
+}
+
+func TestGlobToLike(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"OLD_*", `OLD\_%`},
+		{"DB_?", `DB\__`},
+		{"*", "%"},
+		{"100%", `100\%`},
+		{`back\slash`, `back\\slash`},
+		{"PLAIN", "PLAIN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := GlobToLike(tt.pattern); got != tt.want {
+				t.Errorf("GlobToLike(%q) = %q, want %q", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
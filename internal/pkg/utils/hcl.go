@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// hclIdentifierPattern matches names Terraform accepts as variable names
+var hclIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// reservedTerraformVariables are names Terraform doesn't allow for input variables
+var reservedTerraformVariables = map[string]bool{
+	"source":     true,
+	"version":    true,
+	"providers":  true,
+	"count":      true,
+	"for_each":   true,
+	"lifecycle":  true,
+	"depends_on": true,
+	"locals":     true,
+}
+
+// IsTerraformVariableName reports whether name can be used as a Terraform input variable
+func IsTerraformVariableName(name string) bool {
+	return hclIdentifierPattern.MatchString(name) && !reservedTerraformVariables[name]
+}
+
+// QuoteHCL quotes a value as an HCL string literal. Backslashes, double quotes and
+// control characters are escaped, and "${" and "%{" are doubled so Terraform doesn't
+// treat them as template sequences.
+func QuoteHCL(value string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, r := range value {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '$', '%':
+			b.WriteRune(r)
+			if strings.HasPrefix(value[i+1:], "{") {
+				b.WriteRune(r)
+			}
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
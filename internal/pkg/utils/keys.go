@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrDuplicateKeys is returned when a file sets the same key more than once and
+// duplicates aren't allowed
+var ErrDuplicateKeys = errors.New("duplicate keys")
+
+// DuplicateKey is a key set more than once in the same environment of a file
+type DuplicateKey struct {
+	Environment string
+	Key         string
+	Lines       []int
+}
+
+// String describes the duplicate, e.g. "FOO (lines 2, 7)" or "staging/FOO (lines 2, 7)"
+func (d DuplicateKey) String() string {
+	lines := make([]string, len(d.Lines))
+	for i, line := range d.Lines {
+		lines[i] = fmt.Sprint(line)
+	}
+
+	key := d.Key
+	if d.Environment != "" {
+		key = d.Environment + "/" + key
+	}
+	return fmt.Sprintf("%s (lines %s)", key, strings.Join(lines, ", "))
+}
+
+// FindDuplicateKeys returns the keys set more than once in the same environment of
+// entries, in the order they first appear, with the lines setting them
+func FindDuplicateKeys(entries []EnvEntry) []DuplicateKey {
+	lines := make(map[[2]string][]int)
+	var order [][2]string
+	for _, entry := range entries {
+		id := [2]string{entry.Environment, entry.Key}
+		if _, ok := lines[id]; !ok {
+			order = append(order, id)
+		}
+		lines[id] = append(lines[id], entry.Line)
+	}
+
+	var duplicates []DuplicateKey
+	for _, id := range order {
+		if len(lines[id]) > 1 {
+			duplicates = append(duplicates, DuplicateKey{Environment: id[0], Key: id[1], Lines: lines[id]})
+		}
+	}
+	return duplicates
+}
+
+// ValidateKey returns an error if key can't be a variable key: it must not be empty
+// or contain whitespace or "=", which would make it unreadable in a .env file
+func ValidateKey(key string) error {
+	if key == "" {
+		return invalidValuef("invalid key: keys must not be empty")
+	}
+	if strings.ContainsAny(key, " \t\r\n=") {
+		return invalidValuef("invalid key '%s': keys must not contain whitespace or '='", key)
+	}
+	return nil
+}
+
+// UppercaseKeys returns a copy of entries with every key uppercased. It fails if two
+// distinct keys of the same environment, such as Path and PATH, would become the same
+// key. A key repeated exactly is not a collision.
+func UppercaseKeys(entries []EnvEntry) ([]EnvEntry, error) {
+	// First entry seen for each uppercased key of each environment
+	seen := make(map[[2]string]EnvEntry, len(entries))
+
+	upper := make([]EnvEntry, len(entries))
+	for i, entry := range entries {
+		key := strings.ToUpper(entry.Key)
+		id := [2]string{entry.Environment, key}
+		if first, ok := seen[id]; ok && first.Key != entry.Key {
+			return nil, fmt.Errorf("keys '%s' (line %d) and '%s' (line %d) both become %s",
+				first.Key, first.Line, entry.Key, entry.Line, key)
+		} else if !ok {
+			seen[id] = entry
+		}
+
+		entry.Key = key
+		upper[i] = entry
+	}
+
+	return upper, nil
+}
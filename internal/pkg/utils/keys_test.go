@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		wantErr bool
+	}{
+		{"DATABASE_URL", false},
+		{"app.name", false},
+		{"lower-case", false},
+		{"", true},
+		{"MY KEY", true},
+		{"KEY\t", true},
+		{"A=B", true},
+		{"LINE\nBREAK", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			err := ValidateKey(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateKey(%q) error = %v, want error %v", tt.key, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidValue) {
+				t.Errorf("ValidateKey(%q) error = %v, want ErrInvalidValue", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestUppercaseKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []EnvEntry
+		want    []string
+		wantErr string
+	}{
+		{"none", nil, []string{}, ""},
+		{
+			"uppercases",
+			[]EnvEntry{{Key: "path", Line: 1}, {Key: "Home", Line: 2}, {Key: "USER", Line: 3}},
+			[]string{"PATH", "HOME", "USER"},
+			"",
+		},
+		{
+			"repeated key",
+			[]EnvEntry{{Key: "path", Line: 1}, {Key: "path", Line: 2}},
+			[]string{"PATH", "PATH"},
+			"",
+		},
+		{
+			"collision",
+			[]EnvEntry{{Key: "Path", Line: 1}, {Key: "OTHER", Line: 2}, {Key: "PATH", Line: 3}},
+			nil,
+			"keys 'Path' (line 1) and 'PATH' (line 3) both become PATH",
+		},
+		{
+			"same key in other environments",
+			[]EnvEntry{{Key: "Path", Line: 1, Environment: "dev"}, {Key: "PATH", Line: 2, Environment: "prod"}},
+			[]string{"PATH", "PATH"},
+			"",
+		},
+		{
+			"collision within an environment",
+			[]EnvEntry{{Key: "Path", Line: 1, Environment: "dev"}, {Key: "PATH", Line: 2, Environment: "prod"}, {Key: "path", Line: 3, Environment: "prod"}},
+			nil,
+			"keys 'PATH' (line 2) and 'path' (line 3) both become PATH",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UppercaseKeys(tt.entries)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("UppercaseKeys() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UppercaseKeys() error = %v", err)
+			}
+
+			keys := []string{}
+			for i, entry := range got {
+				keys = append(keys, entry.Key)
+				if entry.Line != tt.entries[i].Line || entry.Environment != tt.entries[i].Environment {
+					t.Errorf("entry %d = %+v, want the line and environment of %+v", i, entry, tt.entries[i])
+				}
+			}
+			if !reflect.DeepEqual(keys, tt.want) {
+				t.Errorf("UppercaseKeys() keys = %v, want %v", keys, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// progressInterval limits how often a Progress redraws its line
+const progressInterval = 100 * time.Millisecond
+
+// Progress shows how far a long operation has got as "label: done/total" on a single
+// line that is redrawn in place. A disabled Progress shows nothing, so callers can
+// report to it whether or not the output is a terminal. It is safe for concurrent use.
+type Progress struct {
+	w       io.Writer
+	label   string
+	enabled bool
+
+	mu    sync.Mutex
+	shown bool
+	drawn time.Time
+}
+
+// NewProgress creates a Progress writing to w, showing nothing unless enabled
+func NewProgress(w io.Writer, label string, enabled bool) *Progress {
+	return &Progress{w: w, label: label, enabled: enabled}
+}
+
+// Update shows that done of total items are done. Updates closer together than
+// progressInterval are skipped, except the one completing the operation.
+func (p *Progress) Update(done, total int) {
+	if !p.enabled {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if done < total && now.Sub(p.drawn) < progressInterval {
+		return
+	}
+	p.drawn = now
+	p.shown = true
+
+	fmt.Fprintf(p.w, "\r%s: %d/%d", p.label, done, total)
+}
+
+// Finish ends the progress line, so the next output starts on a line of its own
+func (p *Progress) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.shown {
+		fmt.Fprintln(p.w)
+		p.shown = false
+	}
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Replacer rewrites every occurrence of a search string, or of the matches of a
+// regular expression, in the strings it is given
+type Replacer struct {
+	search  string
+	replace string
+	re      *regexp.Regexp
+}
+
+// NewReplacer creates a Replacer replacing search with replace. With useRegex, search
+// is a regular expression and replace may refer to its groups as $1 or ${name}.
+func NewReplacer(search, replace string, useRegex bool) (*Replacer, error) {
+	if search == "" {
+		return nil, fmt.Errorf("the search string can't be empty")
+	}
+
+	r := &Replacer{search: search, replace: replace}
+	if useRegex {
+		re, err := regexp.Compile(search)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		r.re = re
+	}
+
+	return r, nil
+}
+
+// Replace returns s with every match replaced, and whether anything was replaced. A
+// match replaced by the same text doesn't count as a change.
+func (r *Replacer) Replace(s string) (string, bool) {
+	var replaced string
+	if r.re != nil {
+		replaced = r.re.ReplaceAllString(s, r.replace)
+	} else {
+		replaced = strings.ReplaceAll(s, r.search, r.replace)
+	}
+	return replaced, replaced != s
+}
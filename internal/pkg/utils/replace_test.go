@@ -0,0 +1,57 @@
+package utils
+
+import "testing"
+
+func TestReplacer(t *testing.T) {
+	tests := []struct {
+		name        string
+		search      string
+		replace     string
+		regex       bool
+		input       string
+		want        string
+		wantChanged bool
+	}{
+		{"plain", "db1.internal", "db2.internal", false, "postgres://db1.internal:5432", "postgres://db2.internal:5432", true},
+		{"every occurrence", "a", "b", false, "banana", "bbnbnb", true},
+		{"no match", "x", "y", false, "banana", "banana", false},
+		{"removes", "_OLD", "", false, "KEY_OLD", "KEY", true},
+		{"same text", "a", "a", false, "banana", "banana", false},
+		{"plain is literal", "a.c", "x", false, "abc a.c", "abc x", true},
+		{"regex", "acct-[0-9]+", "acct-4242", true, "id=acct-17,acct-9", "id=acct-4242,acct-4242", true},
+		{"regex group", `(\w+)@old\.com`, "$1@new.com", true, "ops@old.com", "ops@new.com", true},
+		{"regex named group", `(?P<user>\w+)@old`, "${user}@new", true, "ops@old", "ops@new", true},
+		{"regex no match", "[0-9]+", "N", true, "none", "none", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReplacer(tt.search, tt.replace, tt.regex)
+			if err != nil {
+				t.Fatalf("NewReplacer() error = %v", err)
+			}
+			got, changed := r.Replace(tt.input)
+			if got != tt.want || changed != tt.wantChanged {
+				t.Errorf("Replace(%q) = %q, %v, want %q, %v", tt.input, got, changed, tt.want, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestNewReplacerErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		search string
+		regex  bool
+	}{
+		{"empty", "", false},
+		{"empty regex", "", true},
+		{"invalid regex", "([a-z", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewReplacer(tt.search, "x", tt.regex); err == nil {
+				t.Errorf("NewReplacer(%q) succeeded, want an error", tt.search)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// secretPatterns are well-known credential formats, checked in order
+var secretPatterns = []struct {
+	kind    string
+	pattern *regexp.Regexp
+}{
+	{"an AWS access key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"a private key", regexp.MustCompile(`-----BEGIN ([A-Z0-9]+ )*PRIVATE KEY( BLOCK)?-----`)},
+	{"a GitHub token", regexp.MustCompile(`\b(ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36}\b|\bgithub_pat_[A-Za-z0-9_]{22,}\b`)},
+	{"a Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}\b`)},
+	{"a Stripe secret key", regexp.MustCompile(`\b(sk|rk)_live_[A-Za-z0-9]{16,}\b`)},
+}
+
+// tokenPattern matches values made of the characters random tokens are usually
+// encoded with: base64, base64url and hex
+var tokenPattern = regexp.MustCompile(`^[A-Za-z0-9+/=_-]+$`)
+
+// Values at least minTokenLength long with at least minTokenEntropy bits of entropy
+// per character are reported as high-entropy tokens
+const (
+	minTokenLength  = 20
+	minTokenEntropy = 4.0
+)
+
+// DetectSecret reports whether value looks like a real credential, and if so what
+// kind, such as "an AWS access key". Besides known formats, long random-looking
+// tokens are detected by their entropy.
+func DetectSecret(value string) (string, bool) {
+	for _, p := range secretPatterns {
+		if p.pattern.MatchString(value) {
+			return p.kind, true
+		}
+	}
+
+	if len(value) >= minTokenLength && tokenPattern.MatchString(value) &&
+		strings.ContainsAny(value, "0123456789") && ShannonEntropy(value) >= minTokenEntropy {
+		return "a high-entropy token", true
+	}
+
+	return "", false
+}
+
+// ShannonEntropy returns the Shannon entropy of s in bits per character
+func ShannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
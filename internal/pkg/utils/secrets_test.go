@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDetectSecret(t *testing.T) {
+	// Built from parts so the fake credentials don't trip secret scanners
+	tests := []struct {
+		name     string
+		value    string
+		wantKind string
+	}{
+		{"aws access key", "AKIA" + "IOSFODNN7EXAMPLE", "an AWS access key"},
+		{"aws session key", "id=ASIA" + "IOSFODNN7EXAMPLE;", "an AWS access key"},
+		{"rsa private key", "-----BEGIN RSA " + "PRIVATE KEY-----\nMIIE...", "a private key"},
+		{"openssh private key", "-----BEGIN OPENSSH " + "PRIVATE KEY-----", "a private key"},
+		{"pgp private key", "-----BEGIN PGP " + "PRIVATE KEY BLOCK-----", "a private key"},
+		{"github token", "ghp" + "_" + strings.Repeat("a1B2", 9), "a GitHub token"},
+		{"github fine-grained token", "github" + "_pat_" + strings.Repeat("A1b2_", 5), "a GitHub token"},
+		{"slack token", "xox" + "b-1234567890-abcdefghij", "a Slack token"},
+		{"stripe key", "sk" + "_live_" + strings.Repeat("x1Y2", 5), "a Stripe secret key"},
+		{"random token", "q8Zt3LmX9vR2pK7wY4nB6cJ1", "a high-entropy token"},
+		{"public key", "-----BEGIN PUBLIC KEY-----", ""},
+		{"short", "s3cr3t", ""},
+		{"url", "postgres://localhost:5432/app", ""},
+		{"words", "this-is-not-a-secret-value", ""},
+		{"repetitive", strings.Repeat("ab12", 8), ""},
+		{"no digits", "qZtLmXvRpKwYnBcJaSdFgHjK", ""},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, ok := DetectSecret(tt.value)
+			if ok != (tt.wantKind != "") || kind != tt.wantKind {
+				t.Errorf("DetectSecret(%q) = %q, %v, want %q", tt.value, kind, ok, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestShannonEntropy(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+	}{
+		{"", 0},
+		{"aaaa", 0},
+		{"ab", 1},
+		{"abcd", 2},
+		{"aabb", 1},
+		{"0123456789abcdef", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := ShannonEntropy(tt.value); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ShannonEntropy(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// shellIdentifierPattern matches names that can be used as shell variable names
+var shellIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// shellSafePattern matches values that need no quoting in POSIX shells
+var shellSafePattern = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,=-]+$`)
+
+// IsShellIdentifier reports whether name is a valid shell variable name
+func IsShellIdentifier(name string) bool {
+	return shellIdentifierPattern.MatchString(name)
+}
+
+// QuoteShell quotes a value for bash, zsh and POSIX sh so that it is taken literally
+// when the output is sourced or evaluated. Values made only of safe characters are
+// returned as is; anything else is wrapped in single quotes, inside which "$",
+// backticks, backslashes and double quotes have no special meaning. An embedded
+// single quote is written by closing the quoted string, adding \' and reopening it.
+func QuoteShell(value string) string {
+	if shellSafePattern.MatchString(value) {
+		return value
+	}
+
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// QuotePowerShell quotes a value as a PowerShell double-quoted string. The backtick
+// escape character is used for "$", backticks, double quotes (including the typographic
+// quotes PowerShell also accepts) and control characters, so no expansion takes place.
+func QuotePowerShell(value string) string {
+	replacer := strings.NewReplacer(
+		"`", "``",
+		"$", "`$",
+		`"`, "`\"",
+		"\u201C", "`\u201C",
+		"\u201D", "`\u201D",
+		"\u201E", "`\u201E",
+		"\n", "`n",
+		"\r", "`r",
+		"\t", "`t",
+		"\x00", "`0",
+	)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// QuoteFish quotes a value as a fish single-quoted string, in which only backslashes
+// and single quotes need escaping
+func QuoteFish(value string) string {
+	if shellSafePattern.MatchString(value) {
+		return value
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, "'", `\'`)
+	return "'" + replacer.Replace(value) + "'"
+}
+
+// QuoteSystemd quotes a value for a systemd EnvironmentFile= so that systemd reads it
+// back unchanged. systemd trims the whitespace around unquoted values, drops the
+// backslash of an escape and parses a value starting with a quote as quoted, so
+// values affected by any of that are wrapped in double quotes with backslashes and
+// double quotes escaped. Other values are returned as is. Line breaks can't be
+// represented and are left to the caller to reject.
+func QuoteSystemd(value string) string {
+	if value == "" || (value == strings.TrimSpace(value) && !strings.Contains(value, `\`) &&
+		value[0] != '"' && value[0] != '\'') {
+		return value
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// SplitCommandLine splits a line into words the way a POSIX shell would, without any
+// expansion: words are separated by unquoted whitespace, single quotes keep everything
+// literally, and inside double quotes or outside quotes a backslash escapes the next
+// character.
+func SplitCommandLine(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(c)
+			}
+		case c == '\\':
+			if i+1 == len(runes) {
+				return nil, fmt.Errorf("trailing backslash")
+			}
+			i++
+			word.WriteRune(runes[i])
+			inWord = true
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+		case c == ' ' || c == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unclosed %c quote", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
@@ -0,0 +1,99 @@
+package utils
+
+import "testing"
+
+func TestQuoteShell(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"plain", "plain"},
+		{"postgres://u@h:5432/db", "postgres://u@h:5432/db"},
+		{"", "''"},
+		{"two words", "'two words'"},
+		{`p@ss"w$rd`, `'p@ss"w$rd'`},
+		{"`cmd`", "'`cmd`'"},
+		{`back\slash`, `'back\slash'`},
+		{"it's", `'it'\''s'`},
+		{"line1\nline2", "'line1\nline2'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := QuoteShell(tt.value); got != tt.want {
+				t.Errorf("QuoteShell(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuotePowerShell(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"plain", `"plain"`},
+		{"", `""`},
+		{"$HOME", "\"`$HOME\""},
+		{`say "hi"`, "\"say `\"hi`\"\""},
+		{"tick`", "\"tick``\""},
+		{"“smart”", "\"`“smart`”\""},
+		{"line1\nline2\r\t", "\"line1`nline2`r`t\""},
+		{"nul\x00", "\"nul`0\""},
+		{"it's", `"it's"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := QuotePowerShell(tt.value); got != tt.want {
+				t.Errorf("QuotePowerShell(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteFish(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"plain", "plain"},
+		{"", "''"},
+		{"two words", "'two words'"},
+		{"$HOME", "'$HOME'"},
+		{"it's", `'it\'s'`},
+		{`back\slash`, `'back\\slash'`},
+		{`say "hi"`, `'say "hi"'`},
+		{"(cmd)", "'(cmd)'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := QuoteFish(tt.value); got != tt.want {
+				t.Errorf("QuoteFish(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteSystemd(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"plain", "plain"},
+		{"", ""},
+		{"two words", "two words"},
+		{`say "hi" $HOME`, `say "hi" $HOME`},
+		{`C:\path`, `"C:\\path"`},
+		{`C:\`, `"C:\\"`},
+		{"  x ", `"  x "`},
+		{"x\t", "\"x\t\""},
+		{`"a"`, `"\"a\""`},
+		{"'a'", `"'a'"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := QuoteSystemd(tt.value); got != tt.want {
+				t.Errorf("QuoteSystemd(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
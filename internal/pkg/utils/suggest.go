@@ -0,0 +1,53 @@
+package utils
+
+import "strings"
+
+// Levenshtein returns the edit distance between a and b: the minimum number of
+// single-character insertions, deletions and substitutions turning one into the other
+func Levenshtein(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	if len(s) == 0 {
+		return len(t)
+	}
+	if len(t) == 0 {
+		return len(s)
+	}
+
+	// Only the previous row of the distance matrix is needed
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(t)]
+}
+
+// ClosestMatch returns the candidate closest to target, ignoring case. Candidates
+// further than a third of the target's length (and at least 2 edits) are not
+// considered close enough, in which case ok is false.
+func ClosestMatch(target string, candidates []string) (match string, ok bool) {
+	maxDistance := max(2, len([]rune(target))/3)
+	best := maxDistance + 1
+
+	lower := strings.ToLower(target)
+	for _, c := range candidates {
+		if d := Levenshtein(lower, strings.ToLower(c)); d < best {
+			best, match = d, c
+		}
+	}
+
+	return match, best <= maxDistance
+}
@@ -0,0 +1,62 @@
+package utils
+
+import "testing"
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"prod", "prod", 0},
+		{"prod", "prd", 1},
+		{"prod", "prods", 1},
+		{"prod", "prud", 1},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"Prod", "prod", 1},
+		{"héllo", "hello", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := Levenshtein(tt.a, tt.b); got != tt.want {
+				t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			if got := Levenshtein(tt.b, tt.a); got != tt.want {
+				t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClosestMatch(t *testing.T) {
+	candidates := []string{"production", "staging", "development", "my-api"}
+
+	tests := []struct {
+		target    string
+		want      string
+		wantMatch bool
+	}{
+		{"prodution", "production", true},
+		{"PRODUCTION", "production", true},
+		{"stagin", "staging", true},
+		{"my-apo", "my-api", true},
+		{"devlopment", "development", true},
+		{"qa", "", false},
+		{"something-else", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			got, ok := ClosestMatch(tt.target, candidates)
+			if ok != tt.wantMatch || (ok && got != tt.want) {
+				t.Errorf("ClosestMatch(%q) = %q, %v, want %q, %v", tt.target, got, ok, tt.want, tt.wantMatch)
+			}
+		})
+	}
+
+	if _, ok := ClosestMatch("prod", nil); ok {
+		t.Error("ClosestMatch() with no candidates found a match")
+	}
+}
@@ -0,0 +1,49 @@
+package utils
+
+import "testing"
+
+func TestQuoteTOML(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"plain", "value", `"value"`},
+		{"empty", "", `""`},
+		{"double quotes", `say "hi"`, `"say \"hi\""`},
+		{"single quotes", "it's", `"it's"`},
+		{"backslash", `C:\dir`, `"C:\\dir"`},
+		{"newlines", "line1\nline2\r\n", `"line1\nline2\r\n"`},
+		{"tab", "a\tb", `"a\tb"`},
+		{"other control characters", "bell\a\x1b\x7f", `"bell\u0007\u001B\u007F"`},
+		{"backspace and form feed", "\b\f", `"\b\f"`},
+		{"unicode", "héllo ✓", `"héllo ✓"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := QuoteTOML(tt.value); got != tt.want {
+				t.Errorf("QuoteTOML(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteTOMLKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"DATABASE_URL", "DATABASE_URL"},
+		{"kebab-case", "kebab-case"},
+		{"app.name", `"app.name"`},
+		{"with space", `"with space"`},
+		{`quo"te`, `"quo\"te"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := QuoteTOMLKey(tt.key); got != tt.want {
+				t.Errorf("QuoteTOMLKey(%q) = %s, want %s", tt.key, got, tt.want)
+			}
+		})
+	}
+}
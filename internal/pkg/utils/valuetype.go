@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Value type names accepted by ParseValueType
+const (
+	TypeString = "string"
+	TypeInt    = "int"
+	TypeBool   = "bool"
+	TypeURL    = "url"
+	TypeEnum   = "enum"
+)
+
+// ErrInvalidValue is matched by the errors Validate and ValidateKey return
+var ErrInvalidValue = errors.New("invalid value")
+
+// invalidValueError describes a value rejected by Validate
+type invalidValueError struct {
+	message string
+}
+
+func (e *invalidValueError) Error() string {
+	return e.message
+}
+
+// Is makes the error match ErrInvalidValue without repeating it in the message
+func (e *invalidValueError) Is(target error) bool {
+	return target == ErrInvalidValue
+}
+
+// invalidValuef formats an error that matches ErrInvalidValue
+func invalidValuef(format string, args ...any) error {
+	return &invalidValueError{message: fmt.Sprintf(format, args...)}
+}
+
+// ValueType is the declared type of a variable's value. Enum types carry the list
+// of allowed values in Options.
+type ValueType struct {
+	Name    string
+	Options []string
+}
+
+// ParseValueType parses a type declaration: string, int, bool, url or enum(a,b,c)
+func ParseValueType(s string) (ValueType, error) {
+	s = strings.TrimSpace(s)
+
+	switch strings.ToLower(s) {
+	case TypeString, TypeInt, TypeBool, TypeURL:
+		return ValueType{Name: strings.ToLower(s)}, nil
+	}
+
+	if strings.HasPrefix(strings.ToLower(s), TypeEnum+"(") && strings.HasSuffix(s, ")") {
+		var options []string
+		for _, option := range strings.Split(s[len(TypeEnum)+1:len(s)-1], ",") {
+			if option = strings.TrimSpace(option); option != "" {
+				options = append(options, option)
+			}
+		}
+		if len(options) == 0 {
+			return ValueType{}, fmt.Errorf("enum type needs at least one value, e.g. enum(debug,info)")
+		}
+		return ValueType{Name: TypeEnum, Options: options}, nil
+	}
+
+	return ValueType{}, fmt.Errorf("unknown type '%s' (expected string, int, bool, url or enum(a,b,...))", s)
+}
+
+// String returns the declaration ParseValueType reads back as t
+func (t ValueType) String() string {
+	if t.Name == TypeEnum {
+		return TypeEnum + "(" + strings.Join(t.Options, ",") + ")"
+	}
+	return t.Name
+}
+
+// Validate checks that value is valid for the type, naming key in the error
+func (t ValueType) Validate(key, value string) error {
+	switch t.Name {
+	case TypeInt:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return invalidValuef("%s must be an integer, got '%s'", key, value)
+		}
+	case TypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return invalidValuef("%s must be a boolean (true or false), got '%s'", key, value)
+		}
+	case TypeURL:
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return invalidValuef("%s must be an absolute URL, got '%s'", key, value)
+		}
+	case TypeEnum:
+		for _, option := range t.Options {
+			if value == option {
+				return nil
+			}
+		}
+		return invalidValuef("%s must be one of %s, got '%s'", key, strings.Join(t.Options, ", "), value)
+	}
+
+	return nil
+}
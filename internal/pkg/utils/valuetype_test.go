@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseValueType(t *testing.T) {
+	tests := []struct {
+		declaration string
+		want        ValueType
+		wantErr     bool
+	}{
+		{"string", ValueType{Name: TypeString}, false},
+		{"INT", ValueType{Name: TypeInt}, false},
+		{" bool ", ValueType{Name: TypeBool}, false},
+		{"url", ValueType{Name: TypeURL}, false},
+		{"enum(debug,info)", ValueType{Name: TypeEnum, Options: []string{"debug", "info"}}, false},
+		{"Enum( a , b ,,c )", ValueType{Name: TypeEnum, Options: []string{"a", "b", "c"}}, false},
+		{"enum()", ValueType{}, true},
+		{"enum(a", ValueType{}, true},
+		{"float", ValueType{}, true},
+		{"", ValueType{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.declaration, func(t *testing.T) {
+			got, err := ParseValueType(tt.declaration)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseValueType(%q) error = %v, want error %v", tt.declaration, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseValueType(%q) = %+v, want %+v", tt.declaration, got, tt.want)
+			}
+			if err == nil {
+				if again, _ := ParseValueType(got.String()); !reflect.DeepEqual(again, got) {
+					t.Errorf("ParseValueType(%q) = %+v, want %+v", got.String(), again, got)
+				}
+			}
+		})
+	}
+}
+
+func TestValueTypeValidate(t *testing.T) {
+	level := ValueType{Name: TypeEnum, Options: []string{"debug", "info"}}
+
+	tests := []struct {
+		name    string
+		t       ValueType
+		value   string
+		wantErr string
+	}{
+		{"string", ValueType{Name: TypeString}, "anything at all", ""},
+		{"int", ValueType{Name: TypeInt}, "8080", ""},
+		{"negative int", ValueType{Name: TypeInt}, "-1", ""},
+		{"not an int", ValueType{Name: TypeInt}, "abc", "PORT must be an integer, got 'abc'"},
+		{"float", ValueType{Name: TypeInt}, "1.5", "PORT must be an integer, got '1.5'"},
+		{"empty int", ValueType{Name: TypeInt}, "", "PORT must be an integer, got ''"},
+		{"bool", ValueType{Name: TypeBool}, "true", ""},
+		{"bool digit", ValueType{Name: TypeBool}, "0", ""},
+		{"not a bool", ValueType{Name: TypeBool}, "yes", "PORT must be a boolean (true or false), got 'yes'"},
+		{"url", ValueType{Name: TypeURL}, "https://example.com/path", ""},
+		{"postgres url", ValueType{Name: TypeURL}, "postgres://u:p@db:5432/app", ""},
+		{"relative url", ValueType{Name: TypeURL}, "/path", "PORT must be an absolute URL, got '/path'"},
+		{"url without host", ValueType{Name: TypeURL}, "mailto:ops@example.com", "PORT must be an absolute URL"},
+		{"enum", level, "info", ""},
+		{"enum case", level, "INFO", "PORT must be one of debug, info, got 'INFO'"},
+		{"not in enum", level, "trace", "PORT must be one of debug, info, got 'trace'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.t.Validate("PORT", tt.value)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate(%q) error = %v", tt.value, err)
+				}
+				return
+			}
+			if err == nil || !errors.Is(err, ErrInvalidValue) {
+				t.Fatalf("Validate(%q) error = %v, want ErrInvalidValue", tt.value, err)
+			}
+			if !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("Validate(%q) error = %q, want %q", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}